@@ -0,0 +1,18 @@
+data "gpcloud_inventory" "example" {
+  project_id        = "b194dd6f-21ea-44e0-98b7-b7ac434430d3"
+  group_by          = ["role", "env"]
+  ssh_identity_file = "~/.ssh/id_ed25519"
+  tags = {
+    env = "production"
+  }
+}
+
+resource "local_file" "ansible_inventory" {
+  filename = "${path.module}/inventory.yml"
+  content  = data.gpcloud_inventory.example.ansible_yaml
+}
+
+resource "local_file" "prometheus_targets" {
+  filename = "${path.module}/targets/gpcloud.json"
+  content  = data.gpcloud_inventory.example.prometheus_file_sd
+}
@@ -0,0 +1,9 @@
+data "gpcloud_node" "by_id" {
+  project_id = "b194dd6f-21ea-44e0-98b7-b7ac434430d3"
+  id         = "5b1a6f0e-7c4f-4d2b-9a53-2f5e8c1d9e47"
+}
+
+data "gpcloud_node" "by_fqdn" {
+  project_id = "b194dd6f-21ea-44e0-98b7-b7ac434430d3"
+  fqdn       = "db01.example.com"
+}
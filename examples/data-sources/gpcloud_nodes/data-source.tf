@@ -0,0 +1,12 @@
+data "gpcloud_nodes" "web" {
+  project_id = "b194dd6f-21ea-44e0-98b7-b7ac434430d3"
+  status     = "running"
+  fqdn_regex = "^web[0-9]+\\."
+  tags = {
+    role = "web"
+  }
+}
+
+output "web_ips" {
+  value = [for node in data.gpcloud_nodes.web.nodes : node.ip]
+}
@@ -0,0 +1,5 @@
+# Nodes can be imported using <project_id>/<node_id>
+terraform import gpcloud_node.example aad60ae1-f27f-4f46-9d53-a87e230d4c28/5d5d2c8a-4c1e-4f3c-b0a4-0b8f3c1f5e2a
+
+# or by FQDN, the project is looked up automatically when omitted
+terraform import gpcloud_node.example fqdn:my-node.example.com
@@ -9,4 +9,115 @@ resource "gpcloud_node" "example" {
   tags = {
     "my-custom-tag" = "some-value"
   }
-}
\ No newline at end of file
+}
+# Flavour, datacenter and image can also be referenced by name
+resource "gpcloud_node" "by_name" {
+  project_id     = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  fqdn           = "my-other-node.example.com"
+  image          = "Ubuntu 22.04"
+  ssh_key_ids    = ["90b5d5f1-fc37-457d-9060-a94349be5b5d"]
+  flavour        = "xeon.2288g.128"
+  datacenter     = "fra01"
+  billing_period = "monthly"
+}
+
+# Order the first flavour that is in stock, preferring fra01 over ams01
+resource "gpcloud_node" "fallback" {
+  project_id     = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  fqdn           = "my-fallback-node.example.com"
+  image          = "Ubuntu 22.04"
+  ssh_key_ids    = ["90b5d5f1-fc37-457d-9060-a94349be5b5d"]
+  flavours       = ["xeon.2288g.128", "xeon.2288g.64"]
+  datacenters    = ["fra01", "ams01"]
+  billing_period = "BILLING_PERIOD_MONTHLY"
+}
+
+# The cloud-init configuration can be passed as object, it is rendered to a #cloud-config document
+resource "gpcloud_node" "cloud_config" {
+  project_id     = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  fqdn           = "my-web-node.example.com"
+  image          = "Ubuntu 22.04"
+  ssh_key_ids    = ["90b5d5f1-fc37-457d-9060-a94349be5b5d"]
+  flavour        = "xeon.2288g.128"
+  datacenter     = "fra01"
+  billing_period = "BILLING_PERIOD_MONTHLY"
+  cloud_config = {
+    package_update = true
+    packages       = ["nginx"]
+    write_files = [
+      {
+        path        = "/var/www/html/index.html"
+        content     = "Hello World"
+        permissions = "0644"
+      }
+    ]
+    runcmd = ["systemctl enable --now nginx"]
+  }
+}
+
+# Wait for SSH before running provisioners
+resource "gpcloud_node" "provisioned" {
+  project_id        = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  fqdn              = "my-provisioned-node.example.com"
+  image             = "Ubuntu 22.04"
+  generate_password = true
+  flavour           = "xeon.2288g.128"
+  datacenter        = "fra01"
+  billing_period    = "BILLING_PERIOD_MONTHLY"
+
+  wait_for_ssh {
+    timeout = "15m"
+  }
+
+  connection {
+    host     = self.connection_host
+    user     = self.connection_user
+    password = self.generated_password
+  }
+
+  provisioner "remote-exec" {
+    inline = ["hostname"]
+  }
+}
+
+# The FQDN is generated from the prefix, the datacenter server prefix and a random suffix
+resource "gpcloud_node" "generated_fqdn" {
+  project_id      = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  hostname_prefix = "web"
+  domain          = "example.com"
+  image           = "Ubuntu 22.04"
+  ssh_key_ids     = ["90b5d5f1-fc37-457d-9060-a94349be5b5d"]
+  flavour         = "xeon.2288g.128"
+  datacenter      = "fra01"
+  billing_period  = "BILLING_PERIOD_MONTHLY"
+}
+
+# Mirror the disks and use a custom partitioning
+resource "gpcloud_node" "raid" {
+  project_id          = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  fqdn                = "my-db-node.example.com"
+  image               = "Ubuntu 22.04"
+  ssh_key_ids         = ["90b5d5f1-fc37-457d-9060-a94349be5b5d"]
+  flavour             = "xeon.2288g.128"
+  datacenter          = "fra01"
+  billing_period      = "monthly"
+  reinstall_on_change = true
+
+  disk_layout {
+    raid_level = "raid1"
+
+    partition {
+      mount_point = "/"
+      filesystem  = "ext4"
+      size_gb     = 100
+    }
+    partition {
+      filesystem = "swap"
+      size_gb    = 16
+    }
+    partition {
+      mount_point = "/var/lib/postgresql"
+      filesystem  = "xfs"
+    }
+  }
+}
@@ -0,0 +1,8 @@
+resource "gpcloud_node_action" "example" {
+  project_id = "aad60ae1-f27f-4f46-9d53-a87e230d4c28"
+  node_id    = "5d5d2c8a-4c1e-4f3c-b0a4-0b8f3c1f5e2a"
+  action     = "reboot"
+  triggers = {
+    "maintenance" = "2023-06-01"
+  }
+}
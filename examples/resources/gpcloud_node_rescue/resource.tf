@@ -0,0 +1,14 @@
+resource "gpcloud_node_rescue" "example" {
+  project_id  = "b194dd6f-21ea-44e0-98b7-b7ac434430d3"
+  node_id     = "5b1a6f0e-7c4f-4d2b-9a53-2f5e8c1d9e47"
+  ssh_key_ids = ["8f0c9c3e-3b5e-4f7a-9d2a-6a1e4b7c2d10"]
+}
+
+output "rescue_ssh" {
+  value = "ssh ${gpcloud_node_rescue.example.user}@${gpcloud_node_rescue.example.host}"
+}
+
+output "rescue_password" {
+  value     = gpcloud_node_rescue.example.password
+  sensitive = true
+}
@@ -0,0 +1,5 @@
+# Project Images can be imported using <project_id>/<image_id>
+terraform import gpcloud_project_image.example d6e1052e-20e2-4c70-8bfd-4af4796805d3/0c6a4a37-0d6e-4f53-9e0d-1a3f7f6d7f2b
+
+# or by name, the project is looked up automatically when omitted
+terraform import gpcloud_project_image.example "name:Ubuntu 22.04"
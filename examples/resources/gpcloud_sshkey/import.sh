@@ -0,0 +1,5 @@
+# SSH Keys can be imported using their ID
+terraform import gpcloud_sshkey.example 90b5d5f1-fc37-457d-9060-a94349be5b5d
+
+# or by name
+terraform import gpcloud_sshkey.example name:my-key
@@ -0,0 +1,337 @@
+// Package discovery lists the resources of existing GPCloud projects and renders them as
+// terraform import blocks plus the matching resource configuration.
+package discovery
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	typev1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/type/v1"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Run parses the arguments of the discover subcommand and writes the generated configuration to out.
+func Run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("discover", flag.ContinueOnError)
+	projectID := flags.String("project", "", "ID of the project to discover (required)")
+	endpoint := flags.String("endpoint", os.Getenv("GPCLOUD_ENDPOINT"), "GRPC Address to connect to")
+	clientID := flags.String("client-id", os.Getenv("GPCLOUD_CLIENT_ID"), "Client ID")
+	clientSecret := flags.String("client-secret", os.Getenv("GPCLOUD_CLIENT_SECRET"), "Client Secret")
+	username := flags.String("username", os.Getenv("GPCLOUD_USERNAME"), "User Email Address")
+	password := flags.String("password", os.Getenv("GPCLOUD_PASSWORD"), "Password")
+	realm := flags.String("realm", os.Getenv("GPCLOUD_REALM"), "Keycloak Realm")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *projectID == "" {
+		return fmt.Errorf("the -project flag is required")
+	}
+
+	config := provider.GPCloudProviderModel{
+		Endpoint:     optionalString(*endpoint),
+		ClientID:     types.StringValue(*clientID),
+		ClientSecret: types.StringValue(*clientSecret),
+		Username:     optionalString(*username),
+		Password:     optionalString(*password),
+		Realm:        optionalString(*realm),
+	}
+	gpcloudClient, err := provider.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("unable to create client: %s", err)
+	}
+
+	d := &discoverer{
+		client:    gpcloudClient,
+		projectID: *projectID,
+		names:     map[string]map[string]bool{},
+		dataKeys:  map[string]string{},
+	}
+	if err := d.discover(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, d.render())
+	return err
+}
+
+// discoverer collects the generated blocks and keeps the terraform names unique.
+type discoverer struct {
+	client    *client.Client
+	projectID string
+
+	imports     []string
+	resources   []string
+	dataSources []string
+
+	// names holds the used terraform names per block type
+	names map[string]map[string]bool
+	// dataKeys maps already rendered data sources to their terraform address
+	dataKeys map[string]string
+	// projectImages maps project image IDs to their terraform address
+	projectImages map[string]string
+}
+
+func (d *discoverer) discover() error {
+	sshKeyList, err := d.client.CloudClient().ListUserSSHKeys(context.Background(), &cloudv1.ListUserSSHKeysRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to list ssh keys: %s", err)
+	}
+	sort.Slice(sshKeyList.SshKeys, func(i, j int) bool { return sshKeyList.SshKeys[i].Name < sshKeyList.SshKeys[j].Name })
+	for _, sshKey := range sshKeyList.SshKeys {
+		d.addSSHKey(sshKey)
+	}
+
+	projectImageList, err := d.client.CloudClient().ListProjectImages(context.Background(), &cloudv1.ListProjectImagesRequest{
+		Id: d.projectID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list project images: %s", err)
+	}
+	d.projectImages = map[string]string{}
+	sort.Slice(projectImageList.Images, func(i, j int) bool { return projectImageList.Images[i].Name < projectImageList.Images[j].Name })
+	for _, image := range projectImageList.Images {
+		d.addProjectImage(image)
+	}
+
+	nodeList, err := d.client.CloudClient().ListNodes(context.Background(), &cloudv1.ListNodesRequest{
+		ProjectId: d.projectID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list nodes: %s", err)
+	}
+	sort.Slice(nodeList.Nodes, func(i, j int) bool { return nodeList.Nodes[i].Fqdn < nodeList.Nodes[j].Fqdn })
+	for _, node := range nodeList.Nodes {
+		d.addNode(node)
+	}
+	return nil
+}
+
+func (d *discoverer) addSSHKey(sshKey *typev1.SSHKey) {
+	// Keys rejected by the validator of gpcloud_sshkey, like DSA keys, could not be planned
+	if _, _, err := gpcloudvalidator.ParseSSHPublicKey(sshKey.PublicKey); err != nil {
+		d.resources = append(d.resources, fmt.Sprintf("# SSH key %s (%s) is skipped: %s\n", sanitizeComment(sshKey.Name), sshKey.Id, err))
+		return
+	}
+	name := d.uniqueName("gpcloud_sshkey", sshKey.Name)
+	d.addImport("gpcloud_sshkey."+name, sshKey.Id)
+	d.resources = append(d.resources, block(`resource "gpcloud_sshkey" "`+name+`"`, [][2]string{
+		{"name", hclString(sshKey.Name)},
+		{"public_key", hclString(sshKey.PublicKey)},
+	}))
+}
+
+func (d *discoverer) addProjectImage(image *cloudv1.Image) {
+	name := d.uniqueName("gpcloud_project_image", image.Name)
+	d.projectImages[image.Id] = "gpcloud_project_image." + name
+	d.addImport("gpcloud_project_image."+name, d.projectID+"/"+image.Id)
+	// The source is only used to upload the image, changing it would replace the imported image
+	d.resources = append(d.resources, block(`resource "gpcloud_project_image" "`+name+`"`, [][2]string{
+		{"project_id", hclString(d.projectID)},
+		{"name", hclString(image.Name)},
+		{"source", hclString("unknown")},
+	}, block("lifecycle", [][2]string{
+		{"# The image source can not be discovered", ""},
+		{"ignore_changes", "[source]"},
+	})))
+}
+
+func (d *discoverer) addNode(node *cloudv1.Node) {
+	name := d.uniqueName("gpcloud_node", node.Fqdn)
+	d.addImport("gpcloud_node."+name, node.ProjectId+"/"+node.Id)
+
+	datacenterRef := hclString(node.Datacenter.Id)
+	if node.Datacenter.Short != "" {
+		datacenterRef = d.dataSource("gpcloud_datacenter", node.Datacenter.Short, [][2]string{
+			{"short", hclString(node.Datacenter.Short)},
+		}) + ".id"
+	}
+
+	flavourRef := hclString(node.Flavour.Id)
+	if node.Flavour.Name != "" {
+		flavourRef = d.dataSource("gpcloud_flavour", node.Flavour.Name+"_"+node.Datacenter.Short, [][2]string{
+			{"name", hclString(node.Flavour.Name)},
+			{"project_id", hclString(node.ProjectId)},
+			{"datacenter_id", datacenterRef},
+		}) + ".id"
+	}
+
+	imageRef := hclString(node.Image.Id)
+	if address, ok := d.projectImages[node.Image.Id]; ok {
+		imageRef = address + ".id"
+	} else if node.Image.Name != "" && node.Image.Project == nil {
+		imageRef = d.dataSource("gpcloud_image", node.Image.Name+"_"+node.Flavour.Name, [][2]string{
+			{"name", hclString(node.Image.Name)},
+			{"flavour_id", flavourRef},
+		}) + ".id"
+	}
+
+	attributes := [][2]string{
+		{"project_id", hclString(node.ProjectId)},
+		{"fqdn", hclString(node.Fqdn)},
+		{"flavour_id", flavourRef},
+		{"datacenter_id", datacenterRef},
+		{"image_id", imageRef},
+		{"billing_period", hclString(node.BillingPeriod.String())},
+	}
+	if len(node.Tags) > 0 {
+		attributes = append(attributes, [2]string{"tags", hclMap(node.Tags)})
+	}
+	// Credentials and user data are only used to install the node and can not be read back
+	d.resources = append(d.resources, block(`resource "gpcloud_node" "`+name+`"`, attributes, block("lifecycle", [][2]string{
+		{"# Credentials and user data of existing nodes can not be discovered", ""},
+		{"ignore_changes", "[password, ssh_key_ids, user_data, cloud_config]"},
+	})))
+}
+
+// dataSource renders the data source once and returns its terraform address.
+func (d *discoverer) dataSource(dataType string, key string, attributes [][2]string) string {
+	if address, ok := d.dataKeys[dataType+"/"+key]; ok {
+		return address
+	}
+	name := d.uniqueName("data."+dataType, key)
+	address := "data." + dataType + "." + name
+	d.dataKeys[dataType+"/"+key] = address
+	d.dataSources = append(d.dataSources, block(`data "`+dataType+`" "`+name+`"`, attributes))
+	return address
+}
+
+func (d *discoverer) addImport(address, id string) {
+	d.imports = append(d.imports, block("import", [][2]string{
+		{"to", address},
+		{"id", hclString(id)},
+	}))
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// uniqueName turns the value into a valid terraform name that is not used yet for the block type.
+func (d *discoverer) uniqueName(blockType, value string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(value), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	if d.names[blockType] == nil {
+		d.names[blockType] = map[string]bool{}
+	}
+	unique := name
+	for i := 2; d.names[blockType][unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	d.names[blockType][unique] = true
+	return unique
+}
+
+func (d *discoverer) render() string {
+	var blocks []string
+	blocks = append(blocks, d.imports...)
+	blocks = append(blocks, d.dataSources...)
+	blocks = append(blocks, d.resources...)
+	return strings.Join(blocks, "\n")
+}
+
+// block renders a HCL block with aligned attributes followed by the already rendered nested blocks.
+// Attributes without value are rendered as is, e.g. for comments.
+func block(header string, attributes [][2]string, nested ...string) string {
+	width := 0
+	for _, attribute := range attributes {
+		if attribute[1] != "" && len(attribute[0]) > width {
+			width = len(attribute[0])
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(header + " {\n")
+	for _, attribute := range attributes {
+		if attribute[1] == "" {
+			sb.WriteString("  " + attribute[0] + "\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-*s = %s\n", width, attribute[0], attribute[1]))
+	}
+	for _, nestedBlock := range nested {
+		sb.WriteString("\n")
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(nestedBlock, "\n"), "\n") {
+			sb.WriteString("  " + line)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// hclMap renders the map as HCL object with sorted keys, indented to be used as attribute of a top level block.
+func hclMap(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	width := 0
+	for key := range values {
+		keys = append(keys, key)
+		if len(hclString(key)) > width {
+			width = len(hclString(key))
+		}
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("    %-*s = %s\n", width, hclString(key), hclString(values[key])))
+	}
+	sb.WriteString("  }")
+	return sb.String()
+}
+
+// hclString quotes the value as HCL string literal. Only the escape sequences supported by HCL are used,
+// template sequences are escaped as well.
+func hclString(value string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i, r := range value {
+		switch {
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(value[i+1:], "{"):
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		case unicode.IsPrint(r):
+			sb.WriteRune(r)
+		case r > 0xffff:
+			sb.WriteString(fmt.Sprintf(`\U%08x`, r))
+		default:
+			sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// sanitizeComment keeps the value on a single line of a HCL comment.
+func sanitizeComment(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return ' '
+	}, value)
+}
+
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
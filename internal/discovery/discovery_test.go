@@ -0,0 +1,96 @@
+package discovery
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	typev1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/type/v1"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestRender(t *testing.T) {
+	const projectID = "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"
+	d := &discoverer{
+		projectID:     projectID,
+		names:         map[string]map[string]bool{},
+		dataKeys:      map[string]string{},
+		projectImages: map[string]string{},
+	}
+	d.addSSHKey(&typev1.SSHKey{
+		Id:        "6d5c4b3a-2918-4706-8e5d-4c3b2a190817",
+		Name:      "admin",
+		PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJCKLZxfhUL9hAvYbxQQK5nxlB2f8q5k49ZmjBMOEAWx admin@example.com",
+	})
+	d.addSSHKey(&typev1.SSHKey{
+		Id:        "8f7e6d5c-4b3a-4291-8807-f6e5d4c3b2a1",
+		Name:      "legacy",
+		PublicKey: "ssh-dss AAAAB3NzaC1kc3MAAACBAKYzZcMNMFAQmQJzhZLAnhnJyB7L3c0sPaIEifgc19pPQaqM0wwW4ZXddwbdUohPux6IF2KP993/7K74xdvSzmbmT7zcNmGTck6HoUP0PXUJiCfZ8GTuXtJ0gY0XRUjbXmOhSEr29U9IgA78JVfRjkRKfNMjzdxTRJD/gulbkDsvAAAAFQD+1EMfHA4nmW+mxgpDcSq4S33DLwAAAIBK+N9iMt6Xegp/Vi4pyy3bUH3pOm/9ITDasXp+zWGWikDUAqCLxAQGbZj+LG6CsLUqw3wL9gZnb2PGc8Ln5OwuxviHawP37vOFWJfid3p0sicQj5E7nEFRaElb5RkDGqzhyXGw7fvZSR6dvefM/f4eJqfs1g14uk7tX3Lh7nOe5gAAAIA2BfTnEl/Z+Hf/Shi/CPiusQhNXXLjsIiK2tnTeH+N6YIPkX7XtP88iFL0s17eNGEVETaIu8mD/CHUJjuA6kamU7UArS/hVMptPZNyIQzAwA8FIta3eGhq8lgldmzbpyT5ywS+2ymolkh5161trykoRUhUqg2mMrdjavQxFirYbw== legacy",
+	})
+	projectImage := &cloudv1.Image{
+		Id:      "3c2b1a09-8f7e-4d6c-9b5a-493827160504",
+		Name:    "Debian Custom",
+		Project: &cloudv1.Project{Id: projectID},
+	}
+	d.addProjectImage(projectImage)
+
+	datacenter := &cloudv1.Datacenter{Id: "5c4b3a29-1807-4f6e-8d5c-4b3a29180706", Short: "fra01"}
+	flavour := &cloudv1.Flavour{Id: "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d", Name: "xeon.2288g.128"}
+	d.addNode(&cloudv1.Node{
+		Id:            "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d",
+		ProjectId:     projectID,
+		Fqdn:          "web-01.example.com",
+		Flavour:       flavour,
+		Datacenter:    datacenter,
+		Image:         &cloudv1.Image{Id: "7e6d5c4b-3a29-4180-9f6e-5d4c3b2a1908", Name: "Ubuntu 22.04"},
+		BillingPeriod: cloudv1.BillingPeriod_BILLING_PERIOD_MONTHLY,
+		Tags:          map[string]string{"role": "web", "env": "prod", "team/owner": "${platform}"},
+	})
+	d.addNode(&cloudv1.Node{
+		Id:            "1b0a9c8d-7e6f-4b5a-9d3c-2b1a0f9e8d7c",
+		ProjectId:     projectID,
+		Fqdn:          "db-01.example.com",
+		Flavour:       flavour,
+		Datacenter:    datacenter,
+		Image:         projectImage,
+		BillingPeriod: cloudv1.BillingPeriod_BILLING_PERIOD_HOURLY,
+	})
+
+	golden := filepath.Join("testdata", "render.golden")
+	rendered := d.render()
+	if *update {
+		if err := os.WriteFile(golden, []byte(rendered), 0644); err != nil {
+			t.Fatalf("unable to update golden file: %s", err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("unable to read golden file: %s", err)
+	}
+	if rendered != string(expected) {
+		t.Errorf("rendered configuration does not match %s, run the tests with -update to review the changes:\n%s", golden, rendered)
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"web-01", `"web-01"`},
+		{"say \"hi\"\n", `"say \"hi\"\n"`},
+		{`C:\temp`, `"C:\\temp"`},
+		{"${var} %{if} $$ 100%", `"$${var} %%{if} $$ 100%"`},
+		{"bell\a tab\t vt\v", `"bell\u0007 tab\t vt\u000b"`},
+		{"nul\x00 del\x7f", `"nul\u0000 del\u007f"`},
+		{"München ☃", `"München ☃"`},
+		{"\U000e0001", `"\U000e0001"`},
+	}
+	for _, test := range tests {
+		if quoted := hclString(test.value); quoted != test.expected {
+			t.Errorf("expected %q to be quoted as %s, got %s", test.value, test.expected, quoted)
+		}
+	}
+}
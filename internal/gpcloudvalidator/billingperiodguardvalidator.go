@@ -0,0 +1,38 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/exp/slices"
+)
+
+type BillingPeriodGuardValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v BillingPeriodGuardValidator) Description(ctx context.Context) string {
+	return "Validates the billing period guard."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v BillingPeriodGuardValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures a valid billing period guard is provided"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v BillingPeriodGuardValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if !slices.Contains(validBillingPeriodGuards, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Billing Period Guard",
+			fmt.Sprintf("Invalid billing period guard specified: %s\nValid billing period guards: %v", req.ConfigValue.ValueString(), validBillingPeriodGuards),
+		)
+	}
+}
+
+var validBillingPeriodGuards = []string{"off", "warn", "block"}
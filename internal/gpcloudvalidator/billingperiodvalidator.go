@@ -28,16 +28,27 @@ func (v BillingPeriodValidator) ValidateString(ctx context.Context, req validato
 	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
 		return
 	}
-	if !slices.Contains(validBillingPeriods, req.ConfigValue.ValueString()) {
+	if !slices.Contains(validBillingPeriods, NormalizeBillingPeriod(req.ConfigValue.ValueString())) {
 		resp.Diagnostics.AddAttributeError(
 			req.Path,
 			"Invalid Billing Period",
-			fmt.Sprintf("Invalid billing period specified: %s\nValid billing periods: %v", req.ConfigValue.ValueString(), validBillingPeriods),
+			fmt.Sprintf("Invalid billing period specified: %s\nValid billing periods: %v\nValid aliases: %v", req.ConfigValue.ValueString(), validBillingPeriods, billingPeriodAliases),
 		)
 	}
 }
 
+// NormalizeBillingPeriod returns the enum name for aliases like monthly, other values are returned as they are.
+func NormalizeBillingPeriod(billingPeriod string) string {
+	if slices.Contains(billingPeriodAliases, billingPeriod) {
+		return billingPeriodPrefix + strings.ToUpper(billingPeriod)
+	}
+	return billingPeriod
+}
+
+const billingPeriodPrefix = "BILLING_PERIOD_"
+
 var validBillingPeriods []string
+var billingPeriodAliases []string
 
 func init() {
 	for _, s := range cloudv1.BillingPeriod_name {
@@ -45,5 +56,6 @@ func init() {
 			continue
 		}
 		validBillingPeriods = append(validBillingPeriods, s)
+		billingPeriodAliases = append(billingPeriodAliases, strings.ToLower(strings.TrimPrefix(s, billingPeriodPrefix)))
 	}
 }
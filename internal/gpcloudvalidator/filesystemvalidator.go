@@ -0,0 +1,38 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/exp/slices"
+)
+
+type FilesystemValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v FilesystemValidator) Description(ctx context.Context) string {
+	return "Validates the filesystem."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v FilesystemValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures a valid filesystem is provided"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v FilesystemValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if !slices.Contains(validFilesystems, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Filesystem",
+			fmt.Sprintf("Invalid filesystem specified: %s\nValid filesystems: %v", req.ConfigValue.ValueString(), validFilesystems),
+		)
+	}
+}
+
+var validFilesystems = []string{"ext4", "xfs", "btrfs", "swap"}
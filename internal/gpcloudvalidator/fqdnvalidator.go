@@ -0,0 +1,64 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"regexp"
+	"strings"
+)
+
+var hostnameLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+type FQDNValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v FQDNValidator) Description(ctx context.Context) string {
+	return "Has to be a valid RFC 1123 fully qualified domain name"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v FQDNValidator) MarkdownDescription(ctx context.Context) string {
+	return "Has to be a valid RFC 1123 fully qualified domain name"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v FQDNValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if err := ValidateFQDN(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid FQDN",
+			fmt.Sprintf("The value %q is not a valid FQDN: %s", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+// ValidateFQDN checks the FQDN consists of at least two RFC 1123 labels and does not exceed 253 characters.
+func ValidateFQDN(fqdn string) error {
+	if len(fqdn) > 253 {
+		return fmt.Errorf("it is longer than 253 characters")
+	}
+	labels := strings.Split(fqdn, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("it has to contain a domain")
+	}
+	for _, label := range labels {
+		if err := ValidateHostnameLabel(label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateHostnameLabel checks the label is at most 63 characters long and neither starts nor ends with a hyphen.
+func ValidateHostnameLabel(label string) error {
+	if !hostnameLabelPattern.MatchString(label) {
+		return fmt.Errorf("label %q has to consist of 1 to 63 letters, digits and hyphens, starting and ending with a letter or digit", label)
+	}
+	return nil
+}
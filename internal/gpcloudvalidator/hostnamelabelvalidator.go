@@ -0,0 +1,35 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type HostnameLabelValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v HostnameLabelValidator) Description(ctx context.Context) string {
+	return "Has to be a valid RFC 1123 hostname label"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v HostnameLabelValidator) MarkdownDescription(ctx context.Context) string {
+	return "Has to be a valid RFC 1123 hostname label"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v HostnameLabelValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if err := ValidateHostnameLabel(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Hostname",
+			fmt.Sprintf("The value %q is not a valid hostname: %s", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
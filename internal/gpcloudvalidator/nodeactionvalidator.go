@@ -0,0 +1,38 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/exp/slices"
+)
+
+type NodeActionValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v NodeActionValidator) Description(ctx context.Context) string {
+	return "Validates the node action."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v NodeActionValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures a valid node action is provided"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v NodeActionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if !slices.Contains(validNodeActions, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Node Action",
+			fmt.Sprintf("Invalid node action specified: %s\nValid node actions: %v", req.ConfigValue.ValueString(), validNodeActions),
+		)
+	}
+}
+
+var validNodeActions = []string{"reboot", "reset", "reinstall", "rescue_on", "rescue_off"}
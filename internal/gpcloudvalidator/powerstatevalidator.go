@@ -0,0 +1,38 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/exp/slices"
+)
+
+type PowerStateValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v PowerStateValidator) Description(ctx context.Context) string {
+	return "Validates the power state."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v PowerStateValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures a valid power state is provided"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v PowerStateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if !slices.Contains(validPowerStates, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Power State",
+			fmt.Sprintf("Invalid power state specified: %s\nValid power states: %v", req.ConfigValue.ValueString(), validPowerStates),
+		)
+	}
+}
+
+var validPowerStates = []string{"on", "off"}
@@ -0,0 +1,38 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/exp/slices"
+)
+
+type RaidLevelValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v RaidLevelValidator) Description(ctx context.Context) string {
+	return "Validates the RAID level."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v RaidLevelValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures a valid RAID level is provided"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v RaidLevelValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if !slices.Contains(validRaidLevels, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid RAID Level",
+			fmt.Sprintf("Invalid RAID level specified: %s\nValid RAID levels: %v", req.ConfigValue.ValueString(), validRaidLevels),
+		)
+	}
+}
+
+var validRaidLevels = []string{"none", "raid0", "raid1", "raid5", "raid10"}
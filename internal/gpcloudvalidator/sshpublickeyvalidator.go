@@ -0,0 +1,83 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/exp/slices"
+	"strings"
+)
+
+const minRSAKeyBits = 2048
+
+var validSSHKeyTypes = []string{
+	ssh.KeyAlgoRSA,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoSKECDSA256,
+	ssh.KeyAlgoSKED25519,
+}
+
+type SSHPublicKeyValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v SSHPublicKeyValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Has to be a single public key in authorized_keys format of type %s, RSA keys need at least %d bits", strings.Join(validSSHKeyTypes, ", "), minRSAKeyBits)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v SSHPublicKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Has to be a single public key in authorized_keys format of type `%s`, RSA keys need at least %d bits", strings.Join(validSSHKeyTypes, "`, `"), minRSAKeyBits)
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v SSHPublicKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if _, _, err := ParseSSHPublicKey(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid SSH Public Key",
+			fmt.Sprintf("The value is not a supported SSH public key: %s", err),
+		)
+	}
+}
+
+// ParseSSHPublicKey parses a single public key in authorized_keys format and returns it with its comment.
+// DSA keys, RSA keys shorter than 2048 bits and key options are rejected.
+func ParseSSHPublicKey(value string) (ssh.PublicKey, string, error) {
+	key, comment, options, rest, err := ssh.ParseAuthorizedKey([]byte(value))
+	if err != nil {
+		return nil, "", fmt.Errorf("it is not in authorized_keys format (<type> <base64 key> [comment])")
+	}
+	if len(options) > 0 {
+		return nil, "", fmt.Errorf("key options (%s) are not supported", strings.Join(options, ","))
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, "", fmt.Errorf("it contains more than one key")
+	}
+	if !slices.Contains(validSSHKeyTypes, key.Type()) {
+		return nil, "", fmt.Errorf("key type %s is not supported, use one of %s", key.Type(), strings.Join(validSSHKeyTypes, ", "))
+	}
+	if key.Type() == ssh.KeyAlgoRSA {
+		cryptoKey, ok := key.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, "", fmt.Errorf("unable to read the RSA key")
+		}
+		rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey)
+		if !ok {
+			return nil, "", fmt.Errorf("unable to read the RSA key")
+		}
+		if bits := rsaKey.N.BitLen(); bits < minRSAKeyBits {
+			return nil, "", fmt.Errorf("RSA key has %d bits, at least %d are required", bits, minRSAKeyBits)
+		}
+	}
+	return key, comment, nil
+}
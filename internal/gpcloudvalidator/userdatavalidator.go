@@ -0,0 +1,118 @@
+package gpcloudvalidator
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"gopkg.in/yaml.v3"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+type UserDataValidator struct {
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v UserDataValidator) Description(ctx context.Context) string {
+	return "Validates the cloud-init user data."
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v UserDataValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures `#cloud-config` user data is valid YAML and MIME multipart user data can be parsed"
+}
+
+// ValidateString runs the main validation logic of the validator, reading configuration data out of `req` and updating `resp` with diagnostics.
+func (v UserDataValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	userData := req.ConfigValue.ValueString()
+
+	var err error
+	switch {
+	case strings.HasPrefix(userData, "#cloud-config"):
+		err = validateCloudConfig(userData)
+	case isMultipart(userData):
+		err = validateMultipart(userData)
+	default:
+		// Scripts, includes and other formats are passed to cloud-init as they are
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid User Data",
+			fmt.Sprintf("Invalid user data specified: %s", err),
+		)
+	}
+}
+
+func validateCloudConfig(userData string) error {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(userData), &config); err != nil {
+		return fmt.Errorf("unable to parse cloud-config YAML: %s", err)
+	}
+	return nil
+}
+
+// isMultipart checks if the user data starts with MIME headers declaring a multipart content type,
+// regardless of the header order and the content type parameters.
+func isMultipart(userData string) bool {
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(userData))).ReadMIMEHeader()
+	if err != nil {
+		return false
+	}
+	contentType := strings.ToLower(strings.TrimSpace(header.Get("Content-Type")))
+	return strings.HasPrefix(contentType, "multipart/")
+}
+
+func validateMultipart(userData string) error {
+	message, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		return fmt.Errorf("unable to parse MIME message: %s", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("unable to parse MIME content type: %s", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return fmt.Errorf("MIME content type %s has no multipart boundary", mediaType)
+	}
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to parse MIME part: %s", err)
+		}
+		// Parameters like the charset do not change the part type
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil || partType != "text/cloud-config" {
+			continue
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return fmt.Errorf("unable to read MIME part: %s", err)
+		}
+		if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+			content, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(content)), ""))
+			if err != nil {
+				return fmt.Errorf("unable to decode MIME part: %s", err)
+			}
+		}
+		if err := validateCloudConfig(string(content)); err != nil {
+			return err
+		}
+	}
+}
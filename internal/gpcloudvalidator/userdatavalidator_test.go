@@ -0,0 +1,67 @@
+package gpcloudvalidator
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"testing"
+)
+
+func TestUserDataValidator(t *testing.T) {
+	multipart := func(headers string, parts ...string) string {
+		message := headers + "\n\n"
+		for _, part := range parts {
+			message += "--BOUNDARY\n" + part + "\n"
+		}
+		return message + "--BOUNDARY--\n"
+	}
+	tests := []struct {
+		name     string
+		userData string
+		valid    bool
+	}{
+		{"cloud-config", "#cloud-config\npackages:\n  - nginx\n", true},
+		{"invalid cloud-config", "#cloud-config\npackages: [nginx\n", false},
+		{"script", "#!/bin/sh\necho hello\n", true},
+		{"multipart", multipart("Content-Type: multipart/mixed; boundary=\"BOUNDARY\"",
+			"Content-Type: text/cloud-config\n\npackages:\n  - nginx"), true},
+		{"multipart with MIME-Version first", multipart("MIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=\"BOUNDARY\"",
+			"Content-Type: text/cloud-config\n\npackages: [nginx"), false},
+		{"multipart without boundary", "MIME-Version: 1.0\nContent-Type: multipart/mixed\n\nbody\n", false},
+		{"part with charset", multipart("Content-Type: multipart/mixed; boundary=\"BOUNDARY\"",
+			"Content-Type: text/cloud-config; charset=\"us-ascii\"\n\npackages: [nginx"), false},
+		{"base64 encoded part", multipart("Content-Type: multipart/mixed; boundary=\"BOUNDARY\"",
+			"Content-Type: text/cloud-config\nContent-Transfer-Encoding: base64\n\ncGFja2FnZXM6CiAgLSBuZ2lueAo="), true},
+		{"script part", multipart("MIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=\"BOUNDARY\"",
+			"Content-Type: text/x-shellscript; charset=\"us-ascii\"\n\n#!/bin/sh\necho hello"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			UserDataValidator{}.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("user_data"),
+				ConfigValue: types.StringValue(test.userData),
+			}, resp)
+			if valid := !resp.Diagnostics.HasError(); valid != test.valid {
+				t.Errorf("expected valid to be %t, got diagnostics: %v", test.valid, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestIsMultipart(t *testing.T) {
+	for userData, expected := range map[string]bool{
+		"Content-Type: multipart/mixed; boundary=x\n\n":                    true,
+		"content-type: Multipart/Mixed; boundary=x\n\n":                    true,
+		"MIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=x\n\n": true,
+		"Content-Type: text/cloud-config\n\n":                              false,
+		"#!/bin/sh\necho Content-Type: multipart/mixed\n":                  false,
+		strings.Repeat("x", 10):                                            false,
+	} {
+		if isMultipart(userData) != expected {
+			t.Errorf("expected isMultipart(%q) to be %t", userData, expected)
+		}
+	}
+}
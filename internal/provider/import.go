@@ -0,0 +1,54 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// importID is the parsed form of the IDs accepted by terraform import, which are
+// either <id>, <field>:<value>, <project_id>/<id> or <project_id>/<field>:<value>.
+// Values may contain slashes and colons, e.g. name:web/01.
+type importID struct {
+	ProjectID string
+	Field     string
+	Value     string
+}
+
+func parseImportID(id string) importID {
+	parsed := importID{Field: "id", Value: id}
+	// Only a project ID can precede the slash, otherwise the slash is part of the value
+	if projectID, rest, found := strings.Cut(id, "/"); found && isUUID(projectID) {
+		parsed.ProjectID = projectID
+		parsed.Value = rest
+	}
+	if field, value, found := strings.Cut(parsed.Value, ":"); found {
+		parsed.Field = field
+		parsed.Value = value
+	}
+	return parsed
+}
+
+// isUUID reports whether the value is a UUID in its canonical form.
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil && len(value) == 36
+}
+
+// getImportProjectIDs returns the project given in the import ID, or all projects accessible in case none was given.
+func getImportProjectIDs(client *client.Client, id importID) ([]string, error) {
+	if id.ProjectID != "" {
+		return []string{id.ProjectID}, nil
+	}
+	projectList, err := client.CloudClient().ListProjects(context.Background(), &cloudv1.ListProjectsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projectList.Projects))
+	for _, project := range projectList.Projects {
+		projectIDs = append(projectIDs, project.Id)
+	}
+	return projectIDs, nil
+}
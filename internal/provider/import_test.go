@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestParseImportID(t *testing.T) {
+	const projectID = "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"
+	tests := []struct {
+		id       string
+		expected importID
+	}{
+		{"0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d", importID{Field: "id", Value: "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"}},
+		{projectID + "/0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d", importID{ProjectID: projectID, Field: "id", Value: "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"}},
+		{"fqdn:web-01.example.com", importID{Field: "fqdn", Value: "web-01.example.com"}},
+		{projectID + "/fqdn:web-01.example.com", importID{ProjectID: projectID, Field: "fqdn", Value: "web-01.example.com"}},
+		{"name:web/01", importID{Field: "name", Value: "web/01"}},
+		{projectID + "/name:web/01", importID{ProjectID: projectID, Field: "name", Value: "web/01"}},
+		{"name:db:primary", importID{Field: "name", Value: "db:primary"}},
+		{projectID + "/name:db:primary/eu", importID{ProjectID: projectID, Field: "name", Value: "db:primary/eu"}},
+		{"name:" + projectID + "/backup", importID{Field: "name", Value: projectID + "/backup"}},
+	}
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			if parsed := parseImportID(test.id); parsed != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+)
+
+const defaultPrometheusPort = 9100
+
+var _ datasource.DataSource = &InventoryDataSource{}
+
+func NewInventory() datasource.DataSource {
+	return &InventoryDataSource{}
+}
+
+// InventoryDataSource defines the data source implementation.
+type InventoryDataSource struct {
+	client *client.Client
+}
+
+// InventoryDataSourceModel describes the inventory data model.
+type InventoryDataSourceModel struct {
+	ProjectID        types.String `tfsdk:"project_id"`
+	Tags             types.Map    `tfsdk:"tags"`
+	GroupBy          types.List   `tfsdk:"group_by"`
+	SSHUser          types.String `tfsdk:"ssh_user"`
+	SSHIdentityFile  types.String `tfsdk:"ssh_identity_file"`
+	PrometheusPort   types.Int64  `tfsdk:"prometheus_port"`
+	AnsibleINI       types.String `tfsdk:"ansible_ini"`
+	AnsibleYAML      types.String `tfsdk:"ansible_yaml"`
+	PrometheusFileSD types.String `tfsdk:"prometheus_file_sd"`
+	SSHConfig        types.String `tfsdk:"ssh_config"`
+
+	Id types.String `tfsdk:"id"`
+}
+
+func (d *InventoryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_inventory"
+}
+
+func (d *InventoryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The inventory datasource renders the nodes of a project for other tools, " +
+			"as Ansible inventory (INI and YAML), Prometheus file based service discovery and OpenSSH client configuration.\n\n" +
+			"Hosts are named by their FQDN and ordered by it. Ansible groups are built from the node tags as `<key>_<value>`, " +
+			"with characters not allowed in group names replaced by `_`. Tags that end up with the same group name get a numeric suffix " +
+			"(e.g. `role_web_1` and `role_web_1_2`), the same applies to the Prometheus labels of tag keys.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID to render the nodes of",
+				Required:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Only render nodes having all of these tags with exactly these values",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"group_by": schema.ListAttribute{
+				MarkdownDescription: "Tag keys to build Ansible groups from, defaults to all tags",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"ssh_user": schema.StringAttribute{
+				MarkdownDescription: "User to log in with, defaults to `root`",
+				Optional:            true,
+			},
+			"ssh_identity_file": schema.StringAttribute{
+				MarkdownDescription: "Private key file used by Ansible and `ssh_config`",
+				Optional:            true,
+			},
+			"prometheus_port": schema.Int64Attribute{
+				MarkdownDescription: "Port of the Prometheus targets, defaults to 9100 (node exporter)",
+				Optional:            true,
+			},
+			"ansible_ini": schema.StringAttribute{
+				MarkdownDescription: "Ansible inventory in INI format",
+				Computed:            true,
+			},
+			"ansible_yaml": schema.StringAttribute{
+				MarkdownDescription: "Ansible inventory in YAML format",
+				Computed:            true,
+			},
+			"prometheus_file_sd": schema.StringAttribute{
+				MarkdownDescription: "Prometheus `file_sd_configs` targets in JSON format, labeled with the node ID, FQDN and tags (as `tag_<key>`)",
+				Computed:            true,
+			},
+			"ssh_config": schema.StringAttribute{
+				MarkdownDescription: "OpenSSH client configuration with a `Host` block per node",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Project ID, set for compatibility reasons",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *InventoryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *InventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data InventoryDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags := map[string]string{}
+	for key, value := range data.Tags.Elements() {
+		if stringValue, ok := value.(types.String); ok {
+			tags[key] = stringValue.ValueString()
+		}
+	}
+
+	nodeList, err := d.client.CloudClient().ListNodes(context.Background(), &cloudv1.ListNodesRequest{
+		ProjectId: data.ProjectID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list nodes, got error: %s", err))
+		return
+	}
+
+	var nodes []*cloudv1.Node
+	for _, node := range nodeList.Nodes {
+		if hasTags(node, tags) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Fqdn < nodes[j].Fqdn
+	})
+
+	inventory := inventory{
+		nodes:        nodes,
+		user:         "root",
+		identityFile: data.SSHIdentityFile.ValueString(),
+		port:         defaultPrometheusPort,
+	}
+	if !data.GroupBy.IsNull() {
+		inventory.groupBy = getStrings(data.GroupBy)
+	}
+	if !data.SSHUser.IsNull() {
+		inventory.user = data.SSHUser.ValueString()
+	}
+	if !data.PrometheusPort.IsNull() {
+		inventory.port = data.PrometheusPort.ValueInt64()
+	}
+
+	ansibleYAML, err := inventory.ansibleYAML()
+	if err != nil {
+		resp.Diagnostics.AddError("Inventory Error", fmt.Sprintf("Unable to render Ansible YAML inventory, got error: %s", err))
+		return
+	}
+	prometheusFileSD, err := inventory.prometheusFileSD()
+	if err != nil {
+		resp.Diagnostics.AddError("Inventory Error", fmt.Sprintf("Unable to render Prometheus targets, got error: %s", err))
+		return
+	}
+
+	data.AnsibleINI = types.StringValue(inventory.ansibleINI())
+	data.AnsibleYAML = types.StringValue(ansibleYAML)
+	data.PrometheusFileSD = types.StringValue(prometheusFileSD)
+	data.SSHConfig = types.StringValue(inventory.sshConfig())
+	data.Id = data.ProjectID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// invalidIdentifierChars matches characters not allowed in Ansible group and Prometheus label names.
+var invalidIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// inventory renders nodes for configuration management and monitoring tools.
+type inventory struct {
+	nodes        []*cloudv1.Node
+	groupBy      []string
+	user         string
+	identityFile string
+	port         int64
+}
+
+type prometheusTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// identifier turns the value into a valid Ansible group or Prometheus label name.
+func identifier(value string) string {
+	name := invalidIdentifierChars.ReplaceAllString(value, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// uniqueIdentifiers maps the raw values to the given identifiers, adding a numeric suffix to identifiers that would
+// otherwise be shared by different values (e.g. the tags role=web-1 and role=web.1). The suffixes are assigned in
+// the order of the raw values to keep them stable between reads.
+func uniqueIdentifiers(identifiers map[string]string) map[string]string {
+	values := maps.Keys(identifiers)
+	slices.Sort(values)
+	used := map[string]bool{}
+	unique := map[string]string{}
+	for _, value := range values {
+		name := identifiers[value]
+		for suffix := 2; used[name]; suffix++ {
+			name = fmt.Sprintf("%s_%d", identifiers[value], suffix)
+		}
+		used[name] = true
+		unique[value] = name
+	}
+	return unique
+}
+
+// groups returns the host names of each Ansible group built from the node tags.
+func (i *inventory) groups() map[string][]string {
+	// Tags are looked up by key and value joined by a NUL byte, so a_b=c and a=b_c stay different tags
+	groupNames := map[string]string{}
+	for _, node := range i.nodes {
+		for _, key := range i.groupKeys(node) {
+			groupNames[key+"\x00"+node.Tags[key]] = identifier(key + "_" + node.Tags[key])
+		}
+	}
+	groupNames = uniqueIdentifiers(groupNames)
+
+	groups := map[string][]string{}
+	for _, node := range i.nodes {
+		for _, key := range i.groupKeys(node) {
+			group := groupNames[key+"\x00"+node.Tags[key]]
+			groups[group] = append(groups[group], node.Fqdn)
+		}
+	}
+	return groups
+}
+
+// groupKeys returns the tag keys of the node to build groups from.
+func (i *inventory) groupKeys(node *cloudv1.Node) []string {
+	keys := i.groupBy
+	if keys == nil {
+		keys = maps.Keys(node.Tags)
+	}
+	var groupKeys []string
+	for _, key := range keys {
+		if _, ok := node.Tags[key]; ok {
+			groupKeys = append(groupKeys, key)
+		}
+	}
+	return groupKeys
+}
+
+// hostVars returns the Ansible variables of the node.
+func (i *inventory) hostVars(node *cloudv1.Node) map[string]string {
+	vars := map[string]string{
+		"ansible_user": i.user,
+	}
+	if nodeIP := getPrimaryIP(node); nodeIP != nil {
+		vars["ansible_host"] = *nodeIP
+	}
+	if i.identityFile != "" {
+		vars["ansible_ssh_private_key_file"] = i.identityFile
+	}
+	return vars
+}
+
+func (i *inventory) ansibleINI() string {
+	var ini strings.Builder
+	ini.WriteString("[all]\n")
+	for _, node := range i.nodes {
+		ini.WriteString(node.Fqdn)
+		vars := i.hostVars(node)
+		keys := maps.Keys(vars)
+		slices.Sort(keys)
+		for _, key := range keys {
+			value := vars[key]
+			if strings.ContainsAny(value, " \t\"'") {
+				value = strconv.Quote(value)
+			}
+			ini.WriteString(fmt.Sprintf(" %s=%s", key, value))
+		}
+		ini.WriteString("\n")
+	}
+
+	groups := i.groups()
+	names := maps.Keys(groups)
+	slices.Sort(names)
+	for _, group := range names {
+		ini.WriteString(fmt.Sprintf("\n[%s]\n", group))
+		for _, host := range groups[group] {
+			ini.WriteString(host + "\n")
+		}
+	}
+	return ini.String()
+}
+
+func (i *inventory) ansibleYAML() (string, error) {
+	hosts := map[string]interface{}{}
+	for _, node := range i.nodes {
+		hosts[node.Fqdn] = i.hostVars(node)
+	}
+	all := map[string]interface{}{
+		"hosts": hosts,
+	}
+
+	groups := i.groups()
+	if len(groups) > 0 {
+		children := map[string]interface{}{}
+		for group, groupHosts := range groups {
+			members := map[string]interface{}{}
+			for _, host := range groupHosts {
+				members[host] = nil
+			}
+			children[group] = map[string]interface{}{
+				"hosts": members,
+			}
+		}
+		all["children"] = children
+	}
+
+	// yaml.v3 sorts map keys, which keeps the output stable between reads
+	rendered, err := yaml.Marshal(map[string]interface{}{
+		"all": all,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// prometheusFileSD renders a target group per node, nodes without IP address are left out.
+func (i *inventory) prometheusFileSD() (string, error) {
+	targetGroups := []prometheusTargetGroup{}
+	for _, node := range i.nodes {
+		nodeIP := getPrimaryIP(node)
+		if nodeIP == nil {
+			continue
+		}
+		labels := map[string]string{
+			"gpcloud_node_id": node.Id,
+			"gpcloud_fqdn":    node.Fqdn,
+		}
+		labelNames := map[string]string{}
+		for key := range node.Tags {
+			labelNames[key] = "tag_" + invalidIdentifierChars.ReplaceAllString(key, "_")
+		}
+		for key, labelName := range uniqueIdentifiers(labelNames) {
+			labels[labelName] = node.Tags[key]
+		}
+		targetGroups = append(targetGroups, prometheusTargetGroup{
+			Targets: []string{net.JoinHostPort(*nodeIP, strconv.FormatInt(i.port, 10))},
+			Labels:  labels,
+		})
+	}
+
+	rendered, err := json.MarshalIndent(targetGroups, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(rendered) + "\n", nil
+}
+
+func (i *inventory) sshConfig() string {
+	var config strings.Builder
+	for index, node := range i.nodes {
+		if index > 0 {
+			config.WriteString("\n")
+		}
+		hostName := node.Fqdn
+		if nodeIP := getPrimaryIP(node); nodeIP != nil {
+			hostName = *nodeIP
+		}
+		config.WriteString(fmt.Sprintf("Host %s\n", node.Fqdn))
+		config.WriteString(fmt.Sprintf("  HostName %s\n", hostName))
+		config.WriteString(fmt.Sprintf("  User %s\n", i.user))
+		if i.identityFile != "" {
+			identityFile := i.identityFile
+			if strings.ContainsAny(identityFile, " \t") {
+				identityFile = strconv.Quote(identityFile)
+			}
+			config.WriteString(fmt.Sprintf("  IdentityFile %s\n", identityFile))
+		}
+	}
+	return config.String()
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func testInventory() *inventory {
+	return &inventory{
+		nodes: []*cloudv1.Node{
+			{
+				Id:                "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d",
+				Fqdn:              "db-01.example.com",
+				NetworkInterfaces: []*cloudv1.NetworkInterface{{IpAddresses: []string{"192.0.2.10"}}},
+				Tags:              map[string]string{"role": "db", "env": "prod"},
+			},
+			{
+				Id:                "1b2c3d4e-5f60-4718-9a2b-3c4d5e6f7081",
+				Fqdn:              "web-01.example.com",
+				NetworkInterfaces: []*cloudv1.NetworkInterface{{IpAddresses: []string{"2001:db8::1"}}},
+				Tags:              map[string]string{"role": "web-1", "env": "prod", "team-name": "ops"},
+			},
+			{
+				Id:                "2c3d4e5f-6071-4829-8b3c-4d5e6f708192",
+				Fqdn:              "web-02.example.com",
+				NetworkInterfaces: []*cloudv1.NetworkInterface{{IpAddresses: []string{"192.0.2.12"}}},
+				Tags:              map[string]string{"role": "web.1", "team.name": "dev", "team-name": "ops"},
+			},
+			{
+				Id:   "3d4e5f60-7182-493a-9c4d-5e6f708192a3",
+				Fqdn: "new-01.example.com",
+				Tags: map[string]string{"role": "web_1"},
+			},
+		},
+		user:         "deploy",
+		identityFile: "/home/deploy/.ssh/id ed25519",
+		port:         9100,
+	}
+}
+
+func TestInventoryRender(t *testing.T) {
+	tests := []struct {
+		golden string
+		render func(i *inventory) (string, error)
+	}{
+		{"inventory.ini.golden", func(i *inventory) (string, error) { return i.ansibleINI(), nil }},
+		{"inventory.yaml.golden", (*inventory).ansibleYAML},
+		{"inventory.prometheus.golden", (*inventory).prometheusFileSD},
+		{"inventory.ssh.golden", func(i *inventory) (string, error) { return i.sshConfig(), nil }},
+	}
+	for _, test := range tests {
+		t.Run(test.golden, func(t *testing.T) {
+			rendered, err := test.render(testInventory())
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := os.WriteFile(golden, []byte(rendered), 0644); err != nil {
+					t.Fatalf("unable to update golden file: %s", err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("unable to read golden file: %s", err)
+			}
+			if rendered != string(expected) {
+				t.Errorf("rendered inventory does not match %s, run the tests with -update to review the changes:\n%s", golden, rendered)
+			}
+		})
+	}
+}
+
+func TestInventoryGroupsAreUnique(t *testing.T) {
+	i := testInventory()
+	i.groupBy = []string{"role"}
+	expected := map[string][]string{
+		"role_db":    {"db-01.example.com"},
+		"role_web_1": {"web-01.example.com"},
+		// web.1 and web_1 sort after web-1 and get a suffix
+		"role_web_1_2": {"web-02.example.com"},
+		"role_web_1_3": {"new-01.example.com"},
+	}
+	groups := i.groups()
+	if len(groups) != len(expected) {
+		t.Fatalf("expected groups %v, got %v", expected, groups)
+	}
+	for group, hosts := range expected {
+		if len(groups[group]) != 1 || groups[group][0] != hosts[0] {
+			t.Errorf("expected group %s to contain %v, got %v", group, hosts, groups[group])
+		}
+	}
+}
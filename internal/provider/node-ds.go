@@ -0,0 +1,188 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &NodeDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &NodeDataSource{}
+
+func NewNodeDS() datasource.DataSource {
+	return &NodeDataSource{}
+}
+
+// NodeDataSource defines the data source implementation.
+type NodeDataSource struct {
+	client *client.Client
+}
+
+// NodeDataSourceModel describes the node data model.
+type NodeDataSourceModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	FQDN          types.String `tfsdk:"fqdn"`
+	IP            types.String `tfsdk:"ip"`
+	Status        types.String `tfsdk:"status"`
+	Tags          types.Map    `tfsdk:"tags"`
+	FlavourID     types.String `tfsdk:"flavour_id"`
+	DatacenterID  types.String `tfsdk:"datacenter_id"`
+	ImageID       types.String `tfsdk:"image_id"`
+	BillingPeriod types.String `tfsdk:"billing_period"`
+	Id            types.String `tfsdk:"id"`
+}
+
+func (d *NodeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node"
+}
+
+func (d *NodeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The node datasource looks up a single node of a project by its ID or FQDN, " +
+			"including nodes that are not managed by this terraform configuration.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID the node belongs to",
+				Required:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Node ID, either `id` or `fqdn` has to be set",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"fqdn": schema.StringAttribute{
+				MarkdownDescription: "Fully Qualified Domain Name of the node, either `id` or `fqdn` has to be set",
+				Optional:            true,
+				Computed:            true,
+			},
+			"ip": schema.StringAttribute{
+				MarkdownDescription: "IP Address of the node",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Node Status",
+				Computed:            true,
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Node Tags",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"flavour_id": schema.StringAttribute{
+				MarkdownDescription: "Flavour ID of the node",
+				Computed:            true,
+			},
+			"datacenter_id": schema.StringAttribute{
+				MarkdownDescription: "Datacenter ID the node is located in",
+				Computed:            true,
+			},
+			"image_id": schema.StringAttribute{
+				MarkdownDescription: "Image ID the node is installed with",
+				Computed:            true,
+			},
+			"billing_period": schema.StringAttribute{
+				MarkdownDescription: "Billing period of the node",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NodeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NodeDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data NodeDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Id.IsNull() && !data.FQDN.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdn"), "Invalid Attribute Combination", "Only one of id and fqdn can be set.")
+	}
+	if data.Id.IsNull() && data.FQDN.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Missing Attribute", "Either id or fqdn has to be set.")
+	}
+}
+
+func (d *NodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodeDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	field, value := "id", data.Id.ValueString()
+	if data.Id.IsNull() {
+		field, value = "fqdn", data.FQDN.ValueString()
+	}
+	node, err := findNode(d.client, data.ProjectID.ValueString(), field, value)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get node, got error: %s", err))
+		return
+	}
+	if node == nil {
+		resp.Diagnostics.AddError("Node not found", fmt.Sprintf("Node %s not found in project %s", value, data.ProjectID.ValueString()))
+		return
+	}
+
+	data.write(node)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (data *NodeDataSourceModel) write(node *cloudv1.Node) {
+	data.Id = types.StringValue(node.Id)
+	data.FQDN = types.StringValue(node.Fqdn)
+	data.IP = types.StringNull()
+	if nodeIP := getPrimaryIP(node); nodeIP != nil {
+		data.IP = types.StringValue(*nodeIP)
+	}
+	data.Status = types.StringValue(node.Status.String())
+	data.Tags = getNodeTags(node)
+	data.FlavourID = types.StringValue(node.Flavour.Id)
+	data.DatacenterID = types.StringValue(node.Datacenter.Id)
+	data.ImageID = types.StringValue(node.Image.Id)
+	data.BillingPeriod = types.StringValue(node.BillingPeriod.String())
+}
+
+func getNodeTags(node *cloudv1.Node) types.Map {
+	tags := map[string]attr.Value{}
+	for key, value := range node.Tags {
+		tags[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, tags)
+}
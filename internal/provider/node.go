@@ -6,22 +6,31 @@ import (
 	"fmt"
 	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
 	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"strings"
 	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &Node{}
 var _ resource.ResourceWithImportState = &Node{}
+var _ resource.ResourceWithModifyPlan = &Node{}
+var _ resource.ResourceWithValidateConfig = &Node{}
 
 func NewNode() resource.Resource {
 	return &Node{}
@@ -36,16 +45,47 @@ type Node struct {
 type NodeModel struct {
 	ProjectID     types.String `tfsdk:"project_id"`
 	FlavourID     types.String `tfsdk:"flavour_id"`
+	Flavour       types.String `tfsdk:"flavour"`
+	Flavours      types.List   `tfsdk:"flavours"`
 	DatacenterID  types.String `tfsdk:"datacenter_id"`
+	Datacenter    types.String `tfsdk:"datacenter"`
+	Datacenters   types.List   `tfsdk:"datacenters"`
 	Password      types.String `tfsdk:"password"`
+	GeneratePwd   types.Bool   `tfsdk:"generate_password"`
+	GeneratedPwd  types.String `tfsdk:"generated_password"`
+	HashSecrets   types.Bool   `tfsdk:"hash_secrets"`
 	SSHKeyIDs     types.List   `tfsdk:"ssh_key_ids"`
 	UserData      types.String `tfsdk:"user_data"`
+	UserDataGzip  types.Bool   `tfsdk:"user_data_gzip"`
+	CloudConfig   types.Object `tfsdk:"cloud_config"`
 	FQDN          types.String `tfsdk:"fqdn"`
+	HostPrefix    types.String `tfsdk:"hostname_prefix"`
+	Domain        types.String `tfsdk:"domain"`
 	BillingPeriod types.String `tfsdk:"billing_period"`
+	PendingPeriod types.String `tfsdk:"pending_billing_period"`
 	ImageID       types.String `tfsdk:"image_id"`
+	Image         types.String `tfsdk:"image"`
 	IP            types.String `tfsdk:"ip"`
+	ConnHost      types.String `tfsdk:"connection_host"`
+	ConnUser      types.String `tfsdk:"connection_user"`
+	WaitForSSH    types.Object `tfsdk:"wait_for_ssh"`
+	DiskLayout    types.Object `tfsdk:"disk_layout"`
 	Tags          types.Map    `tfsdk:"tags"`
 	Status        types.String `tfsdk:"status"`
+	StatusMessage types.String `tfsdk:"status_message"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+	FlavourName   types.String `tfsdk:"flavour_name"`
+	Hardware      types.String `tfsdk:"hardware"`
+	DCShort       types.String `tfsdk:"datacenter_short"`
+	ImageName     types.String `tfsdk:"image_name"`
+	PeriodEnd     types.String `tfsdk:"billing_period_end"`
+	Reinstall     types.Bool   `tfsdk:"reinstall_on_change"`
+	PowerState    types.String `tfsdk:"power_state"`
+	RebootTrigger types.String `tfsdk:"reboot_trigger"`
+	DestroyOnFail types.Bool   `tfsdk:"destroy_on_failure"`
+	Protected     types.Bool   `tfsdk:"deletion_protection"`
+	PeriodGuard   types.String `tfsdk:"billing_period_guard"`
 	Id            types.String `tfsdk:"id"`
 }
 
@@ -56,7 +96,9 @@ func (r *Node) Metadata(ctx context.Context, req resource.MetadataRequest, resp
 func (r *Node) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Node is the representation of the Bare Metal Node that got created in the G-PORTAL Cloud.\n\n" +
-			"Changing the Nodes Image ID will cause the Node to be destroyed and recreated.\n\n",
+			"Changing the Nodes Image ID will cause the Node to be destroyed and recreated, unless `reinstall_on_change` is set.\n" +
+			"In that case the Node gets reinstalled in place with the new image, password, SSH keys and user data, keeping its ID and IP addresses.\n\n" +
+			"The plan fails if the flavour can not be ordered in the datacenter or the image is not offered for the flavour.\n\n",
 
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
@@ -67,28 +109,85 @@ func (r *Node) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				},
 			},
 			"flavour_id": schema.StringAttribute{
-				MarkdownDescription: "Node Description",
-				Required:            true,
+				MarkdownDescription: "Flavour ID of the node. One of `flavour_id`, `flavour` or `flavours` has to be set",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Validators: []validator.String{
 					gpcloudvalidator.UUIDStringValidator{},
 				},
 			},
+			"flavour": schema.StringAttribute{
+				MarkdownDescription: "Flavour name of the node (e.g. `xeon.2288g.128`), resolved to `flavour_id` during plan",
+				Optional:            true,
+			},
+			"flavours": schema.ListAttribute{
+				MarkdownDescription: "Acceptable flavour names or IDs in order of preference. The first flavour in stock is ordered, " +
+					"the choice is kept in `flavour_id` and not evaluated again after the node got created",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"datacenter_id": schema.StringAttribute{
-				MarkdownDescription: "Datacenter ID the node is located in",
-				Required:            true,
+				MarkdownDescription: "Datacenter ID the node is located in. One of `datacenter_id`, `datacenter` or `datacenters` has to be set",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Validators: []validator.String{
 					gpcloudvalidator.UUIDStringValidator{},
 				},
 			},
+			"datacenter": schema.StringAttribute{
+				MarkdownDescription: "Short name of the datacenter the node is located in (e.g. `fra01`), resolved to `datacenter_id` during plan",
+				Optional:            true,
+			},
+			"datacenters": schema.ListAttribute{
+				MarkdownDescription: "Acceptable datacenter short names or IDs in order of preference. The first datacenter having the flavour in stock is used, " +
+					"the choice is kept in `datacenter_id` and not evaluated again after the node got created",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Password used for authentication",
+				MarkdownDescription: "Password used for authentication. At least one of `password`, `generate_password` or `ssh_key_ids` has to be set when the node gets created, matching the `authentication_types` of the image",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					secretHashModifier{},
+				},
+			},
+			"generate_password": schema.BoolAttribute{
+				MarkdownDescription: "Generate a strong password for the node, exposed as `generated_password`. Changing it replaces the node",
 				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(
+						generatePasswordRequiresReplace,
+						"Changing generate_password replaces the node.",
+						"Changing `generate_password` replaces the node.",
+					),
+				},
+			},
+			"generated_password": schema.StringAttribute{
+				MarkdownDescription: "Password generated for the node if `generate_password` is set",
+				Computed:            true,
+				Sensitive:           true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"hash_secrets": schema.BoolAttribute{
+				MarkdownDescription: "Only store the SHA-256 of `password` and `user_data` (including the rendered `cloud_config`) in the state. Changes of the configured values are still detected",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"ssh_key_ids": schema.ListAttribute{
-				MarkdownDescription: "SSH Keys used for authentication",
+				MarkdownDescription: "SSH Keys used for authentication. At least one of `password`, `generate_password` or `ssh_key_ids` has to be set when the node gets created, matching the `authentication_types` of the image",
 				Optional:            true,
 				ElementType:         types.StringType,
 				Validators: []validator.List{
@@ -96,37 +195,139 @@ func (r *Node) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				},
 			},
 			"user_data": schema.StringAttribute{
-				MarkdownDescription: "User Data to be provided for cloud-init",
+				MarkdownDescription: "User Data to be provided for cloud-init. `#cloud-config` documents and MIME multipart messages are validated during plan",
 				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					secretHashModifier{},
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UserDataValidator{},
+				},
+			},
+			"user_data_gzip": schema.BoolAttribute{
+				MarkdownDescription: "Compress the user data with gzip and encode it with base64 before passing it to the node, for payloads exceeding the size limit of 64 KiB",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
 			},
+			"cloud_config": cloudConfigAttribute(),
 			"fqdn": schema.StringAttribute{
-				MarkdownDescription: "Fully Qualified Domain Name of the node",
-				Required:            true,
+				MarkdownDescription: "Fully Qualified Domain Name of the node. If not set, it is generated from `hostname_prefix`, " +
+					"the server prefix of the datacenter, a random suffix and `domain` (e.g. `web-fra-k3x9q2.example.com`)",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.FQDNValidator{},
+				},
+			},
+			"hostname_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix of the generated FQDN, required if `fqdn` is not set",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.HostnameLabelValidator{},
+				},
+			},
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "Domain of the generated FQDN, required if `fqdn` is not set",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.FQDNValidator{},
+				},
 			},
 			"ip": schema.StringAttribute{
 				MarkdownDescription: "IP Address of the node",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"billing_period": schema.StringAttribute{
-				MarkdownDescription: "Billing Configuration",
-				Required:            true,
+			"connection_host": schema.StringAttribute{
+				MarkdownDescription: "Host to use in `connection` blocks of provisioners",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"connection_user": schema.StringAttribute{
+				MarkdownDescription: "User to use in `connection` blocks of provisioners",
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
+			},
+			"billing_period": schema.StringAttribute{
+				MarkdownDescription: "Billing Configuration (e.g. `BILLING_PERIOD_MONTHLY` or its alias `monthly`). " +
+					"Changing it switches the billing period of the existing node, see `pending_billing_period`",
+				Required: true,
 				Validators: []validator.String{
 					gpcloudvalidator.BillingPeriodValidator{},
 				},
 			},
+			"pending_billing_period": schema.StringAttribute{
+				MarkdownDescription: "Billing period requested by the last change of `billing_period` that is not active yet, " +
+					"as changes might only take effect at the end of the current billing period. Empty if no change is pending",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"image_id": schema.StringAttribute{
-				MarkdownDescription: "Image ID to install the node with (ID of gpcloud_image or gpcloud_project_image)",
-				Required:            true,
+				MarkdownDescription: "Image ID to install the node with (ID of gpcloud_image or gpcloud_project_image). Either `image_id` or `image` has to be set",
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(
+						imageRequiresReplace,
+						"Changing the image replaces the node unless reinstall_on_change is set.",
+						"Changing the image replaces the node unless `reinstall_on_change` is set.",
+					),
 				},
 				Validators: []validator.String{
 					gpcloudvalidator.UUIDStringValidator{},
 				},
 			},
+			"image": schema.StringAttribute{
+				MarkdownDescription: "Name of the public or project image to install the node with (e.g. `Ubuntu 22.04`), resolved to `image_id` during plan",
+				Optional:            true,
+			},
+			"reinstall_on_change": schema.BoolAttribute{
+				MarkdownDescription: "Reinstall the node in place when `image_id` changes instead of destroying and recreating it",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"destroy_on_failure": schema.BoolAttribute{
+				MarkdownDescription: "Destroy the node in case its creation fails after it got ordered. " +
+					"By default the node is kept and marked as tainted, so it is replaced on the next apply",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Prevent the node from being destroyed or replaced. Has to be set to `false` and applied before the node can be destroyed",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"billing_period_guard": schema.StringAttribute{
+				MarkdownDescription: "Behaviour when the node is planned to be destroyed or replaced before its current monthly or yearly billing period ended " +
+					"(`off`, `warn` or `block`). The plan shows when the billing period ends",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("off"),
+				Validators: []validator.String{
+					gpcloudvalidator.BillingPeriodGuardValidator{},
+				},
+			},
 			"tags": schema.MapAttribute{
 				MarkdownDescription: "Node Tags",
 				Optional:            true,
@@ -136,6 +337,68 @@ func (r *Node) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				MarkdownDescription: "Node Status",
 				Computed:            true,
 			},
+			"status_message": schema.StringAttribute{
+				MarkdownDescription: "Human-readable description of the node status",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time the node got created (RFC 3339)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				MarkdownDescription: "Time the node got updated last (RFC 3339)",
+				Computed:            true,
+			},
+			"flavour_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the flavour",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"hardware": schema.StringAttribute{
+				MarkdownDescription: "Hardware summary of the flavour (e.g. `Intel Xeon E-2288G (8 cores), 128 GB RAM, 2x 960 GB NVMe`)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"datacenter_short": schema.StringAttribute{
+				MarkdownDescription: "Short name of the datacenter (e.g. `fra01`)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the installed image",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"billing_period_end": schema.StringAttribute{
+				MarkdownDescription: "End of the current monthly or yearly billing period (RFC 3339), empty for hourly billing",
+				Computed:            true,
+			},
+			"power_state": schema.StringAttribute{
+				MarkdownDescription: "Desired power state of the node (`on` or `off`)",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.PowerStateValidator{},
+				},
+			},
+			"reboot_trigger": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value, changing it causes the node to be rebooted",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Node ID",
@@ -144,6 +407,10 @@ func (r *Node) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"wait_for_ssh": waitForSSHBlock(),
+			"disk_layout":  diskLayoutBlock(),
+		},
 	}
 }
 
@@ -167,6 +434,367 @@ func (r *Node) Configure(ctx context.Context, req resource.ConfigureRequest, res
 	r.client = client
 }
 
+func (r *Node) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *NodeModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, group := range []struct {
+		names  []string
+		values []attr.Value
+	}{
+		{[]string{"flavour_id", "flavour", "flavours"}, []attr.Value{data.FlavourID, data.Flavour, data.Flavours}},
+		{[]string{"datacenter_id", "datacenter", "datacenters"}, []attr.Value{data.DatacenterID, data.Datacenter, data.Datacenters}},
+		{[]string{"image_id", "image"}, []attr.Value{data.ImageID, data.Image}},
+	} {
+		var set []string
+		for i, value := range group.values {
+			if !value.IsNull() {
+				set = append(set, group.names[i])
+			}
+		}
+		if len(set) > 1 {
+			resp.Diagnostics.AddAttributeError(path.Root(set[1]), "Invalid Attribute Combination", fmt.Sprintf("Only one of %s can be set.", strings.Join(group.names, ", ")))
+		}
+		if len(set) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(group.names[0]), "Missing Attribute", fmt.Sprintf("One of %s has to be set.", strings.Join(group.names, ", ")))
+		}
+	}
+
+	if !data.FQDN.IsNull() && (!data.HostPrefix.IsNull() || !data.Domain.IsNull()) {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdn"), "Invalid Attribute Combination", "Only one of fqdn and hostname_prefix with domain can be set.")
+	}
+	if data.FQDN.IsNull() && (data.HostPrefix.IsNull() || data.Domain.IsNull()) {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdn"), "Missing Attribute", "Either fqdn or hostname_prefix and domain have to be set.")
+	}
+	if !data.Password.IsNull() && data.GeneratePwd.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("generate_password"), "Invalid Attribute Combination", "Only one of password and generate_password can be set.")
+	}
+	if !data.UserData.IsNull() && !data.CloudConfig.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("cloud_config"), "Invalid Attribute Combination", "Only one of user_data and cloud_config can be set.")
+	}
+	layout, diags := data.getDiskLayout(ctx)
+	resp.Diagnostics.Append(diags...)
+	if layout != nil && layout.isKnown() {
+		if err := layout.validate(nil); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("disk_layout"), "Invalid Disk Layout", err.Error())
+		}
+	}
+
+	// The size of the rendered cloud_config is checked during plan, its values might still be unknown here
+	if data.CloudConfig.IsNull() && !data.UserData.IsUnknown() && !data.UserDataGzip.IsUnknown() {
+		_, diags := data.getUserData(ctx)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *Node) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		if !req.State.Raw.IsNull() {
+			r.modifyDestroyPlan(ctx, req, resp)
+		}
+		return
+	}
+
+	var plan, state *NodeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.resolveNames(plan, state, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(plan.planCloudConfig(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The metadata of the state is kept unless the flavour, datacenter or image changes
+	if state != nil && !plan.FlavourID.Equal(state.FlavourID) {
+		plan.FlavourName = types.StringUnknown()
+		plan.Hardware = types.StringUnknown()
+	}
+	if state != nil && !plan.DatacenterID.Equal(state.DatacenterID) {
+		plan.DCShort = types.StringUnknown()
+	}
+	if state != nil && !plan.ImageID.Equal(state.ImageID) {
+		plan.ImageName = types.StringUnknown()
+	}
+	// Generated FQDNs are kept until their parts change
+	if state != nil && !plan.HostPrefix.IsNull() && (!plan.HostPrefix.Equal(state.HostPrefix) || !plan.Domain.Equal(state.Domain)) {
+		plan.FQDN = types.StringUnknown()
+	}
+	if state != nil && !plan.BillingPeriod.IsUnknown() && getBillingPeriod(plan.BillingPeriod) != getBillingPeriod(state.BillingPeriod) {
+		resp.Diagnostics.AddAttributeWarning(path.Root("billing_period"), "Billing Period Change",
+			fmt.Sprintf("Node %s is switched from %s to %s billing. %s", state.FQDN.ValueString(),
+				billingPeriodName(getBillingPeriod(state.BillingPeriod)), billingPeriodName(getBillingPeriod(plan.BillingPeriod)),
+				billingCommitment(getBillingPeriod(plan.BillingPeriod))))
+		plan.PendingPeriod = types.StringUnknown()
+	}
+	// Imported nodes keep the credentials they got installed with, only new nodes need them configured
+	if state == nil && !plan.Password.IsUnknown() && !plan.GeneratePwd.IsUnknown() && !plan.SSHKeyIDs.IsUnknown() &&
+		plan.Password.IsNull() && !plan.GeneratePwd.ValueBool() && len(plan.SSHKeyIDs.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing Authentication",
+			"At least one of password, generate_password or ssh_key_ids has to be set to create the node.")
+		return
+	}
+	if state == nil || !plan.FlavourID.Equal(state.FlavourID) || !plan.DatacenterID.Equal(state.DatacenterID) || !plan.ImageID.Equal(state.ImageID) {
+		image := r.validateOrderable(plan, state, resp)
+		if image != nil {
+			validateAuthentication(plan, image, resp)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	r.validateDiskLayout(ctx, plan, state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+
+	// Image names are only resolved here, after the image_id plan modifiers ran
+	if state != nil && !plan.Image.IsNull() && !plan.ImageID.Equal(state.ImageID) && !plan.Reinstall.ValueBool() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("image_id"))
+	}
+	if state != nil && nodeReplaced(plan, state) {
+		r.guardDestroy(state, &resp.Diagnostics)
+	}
+}
+
+// nodeReplaced reports whether the plan replaces the existing node, following the RequiresReplace plan modifiers of the schema.
+// Their result is not passed to ModifyPlan.
+func nodeReplaced(plan *NodeModel, state *NodeModel) bool {
+	if generatePasswordChanged(plan.GeneratePwd, state.GeneratePwd) {
+		return true
+	}
+	if plan.Reinstall.ValueBool() {
+		return false
+	}
+	return !plan.ImageID.Equal(state.ImageID) || !plan.DiskLayout.Equal(state.DiskLayout)
+}
+
+// resolveNames sets the flavour, datacenter and image IDs of the plan from the configured names.
+// Existing nodes keep the IDs of their state unless the names or the IDs they depend on changed.
+func (r *Node) resolveNames(plan *NodeModel, state *NodeModel, resp *resource.ModifyPlanResponse) {
+	if !plan.Datacenter.IsNull() && (state == nil || !plan.Datacenter.Equal(state.Datacenter)) {
+		plan.DatacenterID = types.StringUnknown()
+		if !plan.Datacenter.IsUnknown() && r.client != nil {
+			datacenter, err := findDatacenter(r.client, plan.Datacenter.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("datacenter"), "Client Error", fmt.Sprintf("Unable to resolve datacenter, got error: %s", err))
+				return
+			}
+			plan.DatacenterID = types.StringValue(datacenter.Id)
+		}
+	}
+
+	if !plan.Flavour.IsNull() && (state == nil || !plan.Flavour.Equal(state.Flavour) || !plan.DatacenterID.Equal(state.DatacenterID)) {
+		plan.FlavourID = types.StringUnknown()
+		if !plan.Flavour.IsUnknown() && !plan.ProjectID.IsUnknown() && !plan.DatacenterID.IsUnknown() && r.client != nil {
+			flavour, err := findFlavour(r.client, plan.ProjectID.ValueString(), plan.DatacenterID.ValueString(), plan.Flavour.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("flavour"), "Client Error", fmt.Sprintf("Unable to resolve flavour, got error: %s", err))
+				return
+			}
+			plan.FlavourID = types.StringValue(flavour.Id)
+		}
+	}
+
+	if !plan.Image.IsNull() && (state == nil || !plan.Image.Equal(state.Image) || !plan.FlavourID.Equal(state.FlavourID)) {
+		plan.ImageID = types.StringUnknown()
+		if !plan.Image.IsUnknown() && !plan.ProjectID.IsUnknown() && !plan.FlavourID.IsUnknown() && r.client != nil {
+			image, err := findImage(r.client, plan.ProjectID.ValueString(), plan.FlavourID.ValueString(), plan.Image.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("image"), "Client Error", fmt.Sprintf("Unable to resolve image, got error: %s", err))
+				return
+			}
+			plan.ImageID = types.StringValue(image.Id)
+		}
+	}
+}
+
+// validateOrderable checks that the flavour can be ordered in the datacenter and the image is offered for the flavour,
+// so incompatible combinations fail before anything is changed. The flavour is only checked when a node gets ordered,
+// a reinstall keeps the node. It returns the image the node will be installed with.
+func (r *Node) validateOrderable(plan *NodeModel, state *NodeModel, resp *resource.ModifyPlanResponse) *cloudv1.Image {
+	if r.client == nil || plan.ProjectID.IsUnknown() || plan.DatacenterID.IsUnknown() || plan.FlavourID.IsUnknown() || plan.ImageID.IsUnknown() {
+		return nil
+	}
+
+	if state == nil || !plan.FlavourID.Equal(state.FlavourID) || !plan.DatacenterID.Equal(state.DatacenterID) {
+		flavours, err := listFlavours(r.client, plan.ProjectID.ValueString(), plan.DatacenterID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to validate flavour, got error: %s", err))
+			return nil
+		}
+		validateFlavour(plan, flavours, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return nil
+		}
+	}
+
+	images, err := listImages(r.client, plan.ProjectID.ValueString(), plan.FlavourID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to validate image, got error: %s", err))
+		return nil
+	}
+	for _, image := range images {
+		if image.Id == plan.ImageID.ValueString() {
+			return image
+		}
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("image_id"), "Image Not Available",
+		fmt.Sprintf("Image %s is not offered for flavour %s. Available images: %s",
+			plan.ImageID.ValueString(), plan.FlavourID.ValueString(), imageNames(images)))
+	return nil
+}
+
+// validateFlavour checks that the flavour is one the project can order in the datacenter and that it is in stock.
+func validateFlavour(plan *NodeModel, flavours []*cloudv1.Flavour, diags *diag.Diagnostics) {
+	index := slices.IndexFunc(flavours, func(flavour *cloudv1.Flavour) bool { return flavour.Id == plan.FlavourID.ValueString() })
+	if index < 0 {
+		diags.AddAttributeError(path.Root("flavour_id"), "Flavour Not Orderable",
+			fmt.Sprintf("Flavour %s can not be ordered for project %s in datacenter %s. Available flavours: %s",
+				plan.FlavourID.ValueString(), plan.ProjectID.ValueString(), plan.DatacenterID.ValueString(), flavourNames(flavours)))
+		return
+	}
+	if !flavours[index].Available {
+		diags.AddAttributeError(path.Root("flavour_id"), "Flavour Not Available",
+			fmt.Sprintf("Flavour %s is out of stock in datacenter %s, use flavours or datacenters to fall back to other ones.",
+				flavours[index].Name, plan.DatacenterID.ValueString()))
+	}
+}
+
+// chooseFlavour sets the first flavour and datacenter combination that is in stock for the project,
+// trying all acceptable flavours in the first datacenter before moving on to the next one.
+func (r *Node) chooseFlavour(data *NodeModel) error {
+	datacenters := getStrings(data.Datacenters)
+	if len(datacenters) == 0 {
+		datacenters = []string{data.DatacenterID.ValueString()}
+		if !data.Datacenter.IsNull() {
+			datacenters = []string{data.Datacenter.ValueString()}
+		}
+	}
+	candidates := getStrings(data.Flavours)
+	if len(candidates) == 0 {
+		candidates = []string{data.FlavourID.ValueString()}
+		if !data.Flavour.IsNull() {
+			candidates = []string{data.Flavour.ValueString()}
+		}
+	}
+
+	for _, datacenterName := range datacenters {
+		datacenter, err := findDatacenter(r.client, datacenterName)
+		if err != nil {
+			return err
+		}
+		flavours, err := listFlavours(r.client, data.ProjectID.ValueString(), datacenter.Id)
+		if err != nil {
+			return err
+		}
+		for _, candidate := range candidates {
+			for _, flavour := range flavours {
+				if (flavour.Id == candidate || strings.EqualFold(flavour.Name, candidate)) && flavour.Available {
+					data.DatacenterID = types.StringValue(datacenter.Id)
+					data.FlavourID = types.StringValue(flavour.Id)
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("none of the flavours %s is in stock in the datacenters %s", strings.Join(candidates, ", "), strings.Join(datacenters, ", "))
+}
+
+// validateAuthentication checks that the configured password and SSH keys match the authentication types of the image.
+func validateAuthentication(plan *NodeModel, image *cloudv1.Image, resp *resource.ModifyPlanResponse) {
+	if len(image.AuthenticationTypes) == 0 || plan.Password.IsUnknown() || plan.SSHKeyIDs.IsUnknown() {
+		return
+	}
+	passwordSupported := slices.Contains(image.AuthenticationTypes, cloudv1.AuthenticationType_AUTHENTICATION_TYPE_PASSWORD)
+	sshSupported := slices.Contains(image.AuthenticationTypes, cloudv1.AuthenticationType_AUTHENTICATION_TYPE_SSH)
+	hasPassword := !plan.Password.IsNull() || plan.GeneratePwd.ValueBool()
+	hasSSHKeys := len(plan.SSHKeyIDs.Elements()) > 0
+
+	if hasPassword && !passwordSupported {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Authentication Not Supported",
+			fmt.Sprintf("Image %s does not support password authentication, use ssh_key_ids instead.", image.Name))
+	}
+	if hasSSHKeys && !sshSupported {
+		resp.Diagnostics.AddAttributeError(path.Root("ssh_key_ids"), "Authentication Not Supported",
+			fmt.Sprintf("Image %s does not support SSH key authentication, use password instead.", image.Name))
+	}
+	if (!hasPassword || !passwordSupported) && (!hasSSHKeys || !sshSupported) && !resp.Diagnostics.HasError() {
+		var required []string
+		if passwordSupported {
+			required = append(required, "password")
+		}
+		if sshSupported {
+			required = append(required, "ssh_key_ids")
+		}
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing Authentication",
+			fmt.Sprintf("Image %s requires one of %s to be set.", image.Name, strings.Join(required, ", ")))
+	}
+}
+
+// modifyDestroyPlan guards the destruction of existing nodes.
+func (r *Node) modifyDestroyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state *NodeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.guardDestroy(state, &resp.Diagnostics)
+}
+
+// guardDestroy checks the deletion protection and the billing period guard of a node planned to be destroyed or replaced.
+func (r *Node) guardDestroy(state *NodeModel, diags *diag.Diagnostics) {
+	if state.Protected.ValueBool() {
+		diags.AddError(
+			"Node Deletion Protected",
+			fmt.Sprintf("Node %s has deletion_protection enabled. Set deletion_protection to false and apply before destroying it.", state.FQDN.ValueString()),
+		)
+		return
+	}
+
+	guard := state.PeriodGuard.ValueString()
+	if guard == "" || guard == "off" || r.client == nil {
+		return
+	}
+
+	nodeResponse, err := r.client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
+		Id:        state.Id.ValueString(),
+		ProjectId: state.ProjectID.ValueString(),
+	})
+	if err != nil {
+		diags.AddWarning("Client Warning", fmt.Sprintf("Unable to check the billing period of node %s, got error: %s", state.FQDN.ValueString(), err))
+		return
+	}
+
+	periodEnd := getBillingPeriodEnd(nodeResponse.Node, state.lastPeriodEnd(), time.Now())
+	if periodEnd == nil {
+		return
+	}
+	message := fmt.Sprintf("Node %s is billed with %s until %s. Destroying it now does not stop the charges for the current billing period.",
+		state.FQDN.ValueString(), nodeResponse.Node.BillingPeriod.String(), periodEnd.Format(time.RFC1123))
+	if guard == "block" {
+		diags.AddError("Billing Period Not Ended", message+" Set billing_period_guard to warn or off to allow destroying it.")
+		return
+	}
+	diags.AddWarning("Billing Period Not Ended", message)
+}
+
 func (r *Node) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *NodeModel
 
@@ -176,30 +804,69 @@ func (r *Node) Create(ctx context.Context, req resource.CreateRequest, resp *res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	powerState := data.PowerState
+
+	if !data.Flavours.IsNull() || !data.Datacenters.IsNull() {
+		if err := r.chooseFlavour(data); err != nil {
+			resp.Diagnostics.AddError("Flavour Not Available", fmt.Sprintf("Unable to order node, got error: %s", err))
+			return
+		}
+	}
+	if data.ImageID.IsUnknown() {
+		// The image name can only be resolved once the flavour got chosen
+		image, err := findImage(r.client, data.ProjectID.ValueString(), data.FlavourID.ValueString(), data.Image.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve image, got error: %s", err))
+			return
+		}
+		data.ImageID = types.StringValue(image.Id)
+	}
+
+	if data.FQDN.IsUnknown() {
+		if err := r.generateFQDN(data); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate FQDN, got error: %s", err))
+			return
+		}
+	}
+
 	createRequest := &cloudv1.CreateNodeRequest{
 		Fqdns:         []string{data.FQDN.ValueString()},
 		ProjectId:     data.ProjectID.ValueString(),
 		FlavourId:     data.FlavourID.ValueString(),
 		DatacenterId:  data.DatacenterID.ValueString(),
 		ImageId:       data.ImageID.ValueString(),
-		BillingPeriod: cloudv1.BillingPeriod(cloudv1.BillingPeriod_value[data.BillingPeriod.ValueString()]),
+		BillingPeriod: getBillingPeriod(data.BillingPeriod),
 	}
 
-	if !data.Password.IsNull() {
-		passwd := data.Password.ValueString()
-		createRequest.Password = &passwd
+	// The plan only holds the hashes of the secrets if hash_secrets is set
+	var config *NodeModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if !data.SSHKeyIDs.IsNull() {
-		for _, sshKeyID := range data.SSHKeyIDs.Elements() {
-			if sshKeyIDString, ok := sshKeyID.(types.String); ok {
-				createRequest.SshKeyIds = append(createRequest.SshKeyIds, sshKeyIDString.ValueString())
-			}
+
+	data.GeneratedPwd = types.StringNull()
+	if data.GeneratePwd.ValueBool() {
+		generated, err := generatePassword()
+		if err != nil {
+			resp.Diagnostics.AddError("Password Generation Error", fmt.Sprintf("Unable to generate password, got error: %s", err))
+			return
 		}
+		data.GeneratedPwd = types.StringValue(generated)
+	}
+	createRequest.Password = data.getPassword(config)
+	createRequest.SshKeyIds = data.getSSHKeyIDs()
+	layout, diags := data.getDiskLayout(ctx)
+	resp.Diagnostics.Append(diags...)
+	if layout != nil {
+		createRequest.DiskLayout = layout.toRequest()
 	}
-	if !data.UserData.IsNull() {
-		userData := data.UserData.ValueString()
-		createRequest.UserData = &userData
+	userData, diags := config.getUserData(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	createRequest.UserData = userData
 
 	createResponse, err := r.client.CloudClient().CreateNode(context.Background(), createRequest)
 	if err != nil {
@@ -209,42 +876,38 @@ func (r *Node) Create(ctx context.Context, req resource.CreateRequest, resp *res
 	nodeData := createResponse.Nodes[0]
 	data.write(nodeData)
 
-	nodeIP := data.getPrimaryIP(nodeData)
-	timeoutAfter := time.Now().Add(5 * time.Minute)
-	for nodeIP == nil {
-		if time.Now().After(timeoutAfter) {
-			resp.Diagnostics.AddError("Timeout Error", "Unable to get node IP address")
+	// Tags can not be passed with the create request, set them right away so tag based automation picks up the node early
+	if len(data.Tags.Elements()) > 0 {
+		taggedNode, err := r.setTags(ctx, data)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update node after creation, got error: %s", err))
+			// The node is not tagged, let the next plan show the missing tags
+			data.Tags = types.MapNull(types.StringType)
+			r.abortCreate(ctx, data, resp)
 			return
 		}
-		time.Sleep(time.Second * 10)
-		if getNodeResponse, err := r.client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
-			Id:        data.Id.ValueString(),
-			ProjectId: data.ProjectID.ValueString(),
-		}); err == nil {
-			data.write(getNodeResponse.Node)
-			nodeIP = data.getPrimaryIP(getNodeResponse.Node)
-		}
+		nodeData = taggedNode
+		data.write(nodeData)
 	}
 
-	// If tags should be added, update the node
-	if len(data.Tags.Elements()) > 0 {
-		updateRequest := &cloudv1.UpdateNodeRequest{
-			Id:        nodeData.Id,
-			ProjectId: nodeData.ProjectId,
-			Fqdn:      &nodeData.Fqdn,
-			Tags:      map[string]string{},
-		}
-		for s, value := range data.Tags.Elements() {
-			if stringValue, ok := value.(types.String); ok {
-				updateRequest.Tags[s] = stringValue.ValueString()
-			}
-		}
-		updateResponse, err := r.client.CloudClient().UpdateNode(context.Background(), updateRequest)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update node after creation, got error: %s", err))
+	if err := r.waitForNode(ctx, data, nodeData); err != nil {
+		resp.Diagnostics.AddError("Timeout Error", err.Error())
+		r.abortCreate(ctx, data, resp)
+		return
+	}
+
+	if powerState.ValueString() == "off" {
+		if err := r.changePowerState(ctx, data, cloudv1.PowerAction_POWER_ACTION_OFF, "off"); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to power off node, got error: %s", err))
+			r.abortCreate(ctx, data, resp)
 			return
 		}
-		data.write(updateResponse.Node)
+	} else if err := data.waitForSSH(ctx); err != nil {
+		resp.Diagnostics.AddError("Timeout Error", err.Error())
+		r.abortCreate(ctx, data, resp)
+		return
+	} else if data.PowerState.IsUnknown() {
+		data.PowerState = types.StringValue("on")
 	}
 
 	tflog.Trace(ctx, fmt.Sprintf("Created node with ID: %s", data.Id.ValueString()))
@@ -272,6 +935,7 @@ func (r *Node) Read(ctx context.Context, req resource.ReadRequest, resp *resourc
 			return
 		}
 		data.write(nodeResponse.Node)
+		data.fillMissing(nodeResponse.Node)
 
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
@@ -288,6 +952,68 @@ func (r *Node) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 		return
 	}
 
+	var state *NodeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	powerState := data.PowerState
+	// The billing period end is unknown in the plan, it continues from the one in the state
+	data.PeriodEnd = state.PeriodEnd
+
+	// Image and disk layout changes only reach Update when reinstall_on_change is set
+	if !data.ImageID.Equal(state.ImageID) || !data.DiskLayout.Equal(state.DiskLayout) {
+		reinstallRequest := &cloudv1.ReinstallNodeRequest{
+			Id:        data.Id.ValueString(),
+			ProjectId: data.ProjectID.ValueString(),
+			ImageId:   data.ImageID.ValueString(),
+			SshKeyIds: data.getSSHKeyIDs(),
+		}
+		var config *NodeModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		reinstallRequest.Password = data.getPassword(config)
+		layout, diags := data.getDiskLayout(ctx)
+		resp.Diagnostics.Append(diags...)
+		if layout != nil {
+			reinstallRequest.DiskLayout = layout.toRequest()
+		}
+		userData, diags := config.getUserData(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		reinstallRequest.UserData = userData
+
+		reinstallResponse, err := r.client.CloudClient().ReinstallNode(context.Background(), reinstallRequest)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reinstall node, got error: %s", err))
+			return
+		}
+		node, err := waitForRestart(ctx, r.client, data.ProjectID.ValueString(), data.Id.ValueString(), reinstallResponse.Node, "reinstalling", 30*time.Minute)
+		if node != nil {
+			data.write(node)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Timeout Error", err.Error())
+			return
+		}
+		if err := data.waitForSSH(ctx); err != nil {
+			resp.Diagnostics.AddError("Timeout Error", err.Error())
+			return
+		}
+		tflog.Trace(ctx, fmt.Sprintf("Reinstalled node %s with image %s", data.Id.ValueString(), data.ImageID.ValueString()))
+	}
+
+	if data.FQDN.IsUnknown() {
+		if err := r.generateFQDN(data); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate FQDN, got error: %s", err))
+			return
+		}
+	}
 	fqdn := data.FQDN.ValueString()
 	updateRequest := &cloudv1.UpdateNodeRequest{
 		Id:        data.Id.ValueString(),
@@ -295,6 +1021,10 @@ func (r *Node) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 		Fqdn:      &fqdn,
 		Tags:      map[string]string{},
 	}
+	if billingPeriod := getBillingPeriod(data.BillingPeriod); billingPeriod != getBillingPeriod(state.BillingPeriod) {
+		updateRequest.BillingPeriod = &billingPeriod
+		data.PendingPeriod = types.StringValue(billingPeriod.String())
+	}
 
 	for s, value := range data.Tags.Elements() {
 		if stringValue, ok := value.(types.String); ok {
@@ -308,6 +1038,23 @@ func (r *Node) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 		return
 	}
 	data.write(updateResponse.Node)
+
+	if !powerState.Equal(state.PowerState) {
+		action := cloudv1.PowerAction_POWER_ACTION_ON
+		if powerState.ValueString() == "off" {
+			action = cloudv1.PowerAction_POWER_ACTION_OFF
+		}
+		if err := r.changePowerState(ctx, data, action, powerState.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to change node power state, got error: %s", err))
+			return
+		}
+	} else if !data.RebootTrigger.Equal(state.RebootTrigger) && powerState.ValueString() == "on" {
+		if err := r.changePowerState(ctx, data, cloudv1.PowerAction_POWER_ACTION_REBOOT, "on"); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reboot node, got error: %s", err))
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 
 	tflog.Trace(ctx, fmt.Sprintf("Updated node: %s", data.Id.ValueString()))
@@ -323,6 +1070,14 @@ func (r *Node) Delete(ctx context.Context, req resource.DeleteRequest, resp *res
 		return
 	}
 
+	if data.Protected.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Node Deletion Protected",
+			fmt.Sprintf("Node %s has deletion_protection enabled. Set deletion_protection to false and apply before destroying it.", data.FQDN.ValueString()),
+		)
+		return
+	}
+
 	_, err := r.client.CloudClient().DestroyNode(context.Background(), &cloudv1.DestroyNodeRequest{
 		Id:        data.Id.ValueString(),
 		ProjectId: data.ProjectID.ValueString(),
@@ -337,11 +1092,365 @@ func (r *Node) Delete(ctx context.Context, req resource.DeleteRequest, resp *res
 	}
 }
 
+// ImportState accepts <project_id>/<id> or <project_id>/fqdn:<fqdn>. Without the project, all accessible projects are searched.
 func (r *Node) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id := parseImportID(req.ID)
+	if id.Field != "id" && id.Field != "fqdn" {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Unsupported import field %q, expected <project_id>/<id> or <project_id>/fqdn:<fqdn>", id.Field))
+		return
+	}
+
+	projectIDs, err := getImportProjectIDs(r.client, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list projects, got error: %s", err))
+		return
+	}
+
+	for _, projectID := range projectIDs {
+		node, err := findNode(r.client, projectID, id.Field, id.Value)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to look up node in project %s, got error: %s", projectID, err))
+			return
+		}
+		if node != nil {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), node.Id)...)
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), node.ProjectId)...)
+			return
+		}
+	}
+	resp.Diagnostics.AddError("Node Not Found", fmt.Sprintf("Unable to find node with %s %s", id.Field, id.Value))
+}
+
+// findNode returns the node with the id or fqdn within the project, or nil if there is none.
+func findNode(client *client.Client, projectID string, field string, value string) (*cloudv1.Node, error) {
+	if field == "id" {
+		nodeResponse, err := client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
+			Id:        value,
+			ProjectId: projectID,
+		})
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nodeResponse.Node, nil
+	}
+
+	nodeList, err := client.CloudClient().ListNodes(context.Background(), &cloudv1.ListNodesRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodeList.Nodes {
+		if strings.EqualFold(node.Fqdn, value) {
+			return node, nil
+		}
+	}
+	return nil, nil
 }
 
-func (nodeModel *NodeModel) getPrimaryIP(node *cloudv1.Node) *string {
+// setTags applies the tags of the model to the freshly created node.
+func (r *Node) setTags(ctx context.Context, data *NodeModel) (*cloudv1.Node, error) {
+	return tagNewNode(ctx, r.client, data.ProjectID.ValueString(), data.Id.ValueString(), data.FQDN.ValueString(), data.Tags)
+}
+
+// tagNewNode applies the tags to a freshly created node. As the node might not accept updates
+// right after it got ordered, the update is retried with an increasing delay.
+func tagNewNode(ctx context.Context, client *client.Client, projectID, nodeID, fqdn string, tags types.Map) (*cloudv1.Node, error) {
+	updateRequest := &cloudv1.UpdateNodeRequest{
+		Id:        nodeID,
+		ProjectId: projectID,
+		Fqdn:      &fqdn,
+		Tags:      map[string]string{},
+	}
+	for s, value := range tags.Elements() {
+		if stringValue, ok := value.(types.String); ok {
+			updateRequest.Tags[s] = stringValue.ValueString()
+		}
+	}
+
+	delay := 2 * time.Second
+	for attempt := 1; ; attempt++ {
+		updateResponse, err := client.CloudClient().UpdateNode(context.Background(), updateRequest)
+		if err == nil {
+			return updateResponse.Node, nil
+		}
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated:
+			return nil, err
+		}
+		if attempt == 6 {
+			return nil, err
+		}
+		tflog.Trace(ctx, fmt.Sprintf("Setting tags on node %s failed (attempt %d), retrying in %s: %s", nodeID, attempt, delay, err))
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// abortCreate handles a node whose creation failed after it got ordered. The node is written to the state,
+// which lets terraform mark it as tainted instead of losing track of billable hardware. With destroy_on_failure
+// set, the node is destroyed instead.
+func (r *Node) abortCreate(ctx context.Context, data *NodeModel, resp *resource.CreateResponse) {
+	if data.DestroyOnFail.ValueBool() {
+		_, err := r.client.CloudClient().DestroyNode(context.Background(), &cloudv1.DestroyNodeRequest{
+			Id:        data.Id.ValueString(),
+			ProjectId: data.ProjectID.ValueString(),
+		})
+		if err == nil || status.Code(err) == codes.NotFound {
+			tflog.Trace(ctx, fmt.Sprintf("Destroyed partially created node: %s", data.Id.ValueString()))
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to destroy partially created node, got error: %s", err))
+	}
+
+	// Unknown values can not be stored in the state
+	if data.IP.IsUnknown() {
+		data.IP = types.StringNull()
+	}
+	if data.ConnHost.IsUnknown() {
+		data.ConnHost = types.StringNull()
+	}
+	if data.PowerState.IsUnknown() {
+		data.PowerState = types.StringNull()
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// waitForNode polls the node until it got a primary IP address assigned.
+func (r *Node) waitForNode(ctx context.Context, data *NodeModel, node *cloudv1.Node) error {
+	node, err := waitForNodeCondition(ctx, r.client, data.ProjectID.ValueString(), data.Id.ValueString(), node, 5*time.Minute, func(node *cloudv1.Node) bool {
+		return getPrimaryIP(node) != nil
+	})
+	if node != nil {
+		data.write(node)
+	}
+	if err != nil {
+		return fmt.Errorf("Unable to get node IP address: %s", err)
+	}
+	return nil
+}
+
+// waitForRestart waits for the node to leave the running state after a reinstall, reboot or reset got triggered and to be
+// running again, so the node is not mistaken as done before the action took effect. The operation names the action in errors.
+func waitForRestart(ctx context.Context, client *client.Client, projectID, nodeID string, node *cloudv1.Node, operation string, timeout time.Duration) (*cloudv1.Node, error) {
+	node, err := waitForNodeCondition(ctx, client, projectID, nodeID, node, 5*time.Minute, func(node *cloudv1.Node) bool {
+		return node.Status != cloudv1.NodeStatus_NODE_STATUS_RUNNING
+	})
+	if err != nil {
+		return node, fmt.Errorf("Node %s did not start %s: %s", nodeID, operation, err)
+	}
+	node, err = waitForNodeCondition(ctx, client, projectID, nodeID, node, timeout, func(node *cloudv1.Node) bool {
+		return node.Status == cloudv1.NodeStatus_NODE_STATUS_RUNNING && getPrimaryIP(node) != nil
+	})
+	if err != nil {
+		return node, fmt.Errorf("Node %s did not finish %s: %s", nodeID, operation, err)
+	}
+	return node, nil
+}
+
+// waitForNodeCondition waits until the condition is met using the poller shared across all resources of the provider.
+// It returns the last node fetched and an error if the condition was not met within the timeout.
+func waitForNodeCondition(ctx context.Context, client *client.Client, projectID, nodeID string, node *cloudv1.Node, timeout time.Duration, condition func(node *cloudv1.Node) bool) (*cloudv1.Node, error) {
+	return getNodePoller(client).wait(ctx, projectID, nodeID, node, timeout, condition)
+}
+
+// changePowerState triggers the power action and waits for the node to reach the expected power state.
+func (r *Node) changePowerState(ctx context.Context, data *NodeModel, action cloudv1.PowerAction, expected string) error {
+	_, err := r.client.CloudClient().PowerActionNode(context.Background(), &cloudv1.PowerActionNodeRequest{
+		Id:        data.Id.ValueString(),
+		ProjectId: data.ProjectID.ValueString(),
+		Action:    action,
+	})
+	if err != nil {
+		return err
+	}
+
+	var node *cloudv1.Node
+	if action == cloudv1.PowerAction_POWER_ACTION_REBOOT {
+		// A rebooting node is still running right after the request, it has to go down before it is seen as rebooted
+		node, err = waitForRestart(ctx, r.client, data.ProjectID.ValueString(), data.Id.ValueString(), nil, "rebooting", 10*time.Minute)
+	} else {
+		node, err = waitForNodeCondition(ctx, r.client, data.ProjectID.ValueString(), data.Id.ValueString(), nil, 10*time.Minute, func(node *cloudv1.Node) bool {
+			return powerStateFromStatus(node.Status) == expected
+		})
+	}
+	if node != nil {
+		data.write(node)
+	}
+	if err != nil {
+		return fmt.Errorf("node did not reach power state %s: %s", expected, err)
+	}
+	data.PowerState = types.StringValue(expected)
+	return nil
+}
+
+// getBillingPeriod returns the billing period enum of the configured value, which may be an alias like monthly.
+func getBillingPeriod(billingPeriod types.String) cloudv1.BillingPeriod {
+	return cloudv1.BillingPeriod(cloudv1.BillingPeriod_value[gpcloudvalidator.NormalizeBillingPeriod(billingPeriod.ValueString())])
+}
+
+func billingPeriodName(billingPeriod cloudv1.BillingPeriod) string {
+	return strings.ToLower(strings.TrimPrefix(billingPeriod.String(), "BILLING_PERIOD_"))
+}
+
+// billingCommitment describes what the billing period commits to.
+func billingCommitment(billingPeriod cloudv1.BillingPeriod) string {
+	switch billingPeriod {
+	case cloudv1.BillingPeriod_BILLING_PERIOD_HOURLY:
+		return "Every started hour is charged, there is no commitment beyond the current hour."
+	case cloudv1.BillingPeriod_BILLING_PERIOD_MONTHLY:
+		return "This commits to full months: every started month is charged in full, even if the node is destroyed before the month ends."
+	case cloudv1.BillingPeriod_BILLING_PERIOD_YEARLY:
+		return "This commits to full years: every started year is charged in full, even if the node is destroyed before the year ends."
+	}
+	return ""
+}
+
+// getBillingPeriodEnd returns the end of the current billing period of a monthly or yearly billed node, hourly billed nodes
+// return nil. Periods renew every month or year with the current billing period of the node, starting from the end of the
+// last known period, as a changed billing period only takes effect once the running period ended. Without a known period
+// end, the periods start when the node is created.
+func getBillingPeriodEnd(node *cloudv1.Node, lastPeriodEnd *time.Time, now time.Time) *time.Time {
+	var months int
+	switch node.BillingPeriod {
+	case cloudv1.BillingPeriod_BILLING_PERIOD_MONTHLY:
+		months = 1
+	case cloudv1.BillingPeriod_BILLING_PERIOD_YEARLY:
+		months = 12
+	default:
+		return nil
+	}
+	var periodStart time.Time
+	switch {
+	case lastPeriodEnd != nil:
+		periodStart = *lastPeriodEnd
+	case node.CreatedAt != nil:
+		periodStart = node.CreatedAt.AsTime()
+	default:
+		return nil
+	}
+	// Every period end is computed from the period start, so a short month does not shift all following period ends
+	periodEnd := periodStart
+	for periods := 1; !periodEnd.After(now); periods++ {
+		periodEnd = addMonths(periodStart, months*periods)
+	}
+	return &periodEnd
+}
+
+// addMonths adds the months to the time, the day is clamped to the end of shorter months (e.g. January 31 + 1 month is February 28).
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if lastDay := firstOfMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// lastPeriodEnd returns the billing period end stored in the state, if any.
+func (nodeModel *NodeModel) lastPeriodEnd() *time.Time {
+	if nodeModel.PeriodEnd.IsNull() || nodeModel.PeriodEnd.IsUnknown() {
+		return nil
+	}
+	periodEnd, err := time.Parse(time.RFC3339, nodeModel.PeriodEnd.ValueString())
+	if err != nil {
+		return nil
+	}
+	return &periodEnd
+}
+
+// powerStateFromStatus maps the node status onto the values used by power_state, transitional states map to an empty string.
+func powerStateFromStatus(nodeStatus cloudv1.NodeStatus) string {
+	switch nodeStatus {
+	case cloudv1.NodeStatus_NODE_STATUS_RUNNING:
+		return "on"
+	case cloudv1.NodeStatus_NODE_STATUS_STOPPED:
+		return "off"
+	}
+	return ""
+}
+
+// imageRequiresReplace forces a replacement on image changes unless the node should be reinstalled in place.
+func imageRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var reinstall types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("reinstall_on_change"), &reinstall)...)
+	resp.RequiresReplace = !reinstall.ValueBool()
+}
+
+// generatePasswordRequiresReplace forces a replacement only if generate_password actually changes.
+func generatePasswordRequiresReplace(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = generatePasswordChanged(req.PlanValue, req.StateValue)
+}
+
+// generatePasswordChanged compares generate_password, treating null as false. Nodes created by previous versions
+// of the provider and imported nodes have no value in their state, which must not replace them.
+func generatePasswordChanged(plan types.Bool, state types.Bool) bool {
+	return plan.IsUnknown() || plan.ValueBool() != state.ValueBool()
+}
+
+func (nodeModel *NodeModel) getSSHKeyIDs() []string {
+	var sshKeyIDs []string
+	for _, sshKeyID := range nodeModel.SSHKeyIDs.Elements() {
+		if sshKeyIDString, ok := sshKeyID.(types.String); ok {
+			sshKeyIDs = append(sshKeyIDs, sshKeyIDString.ValueString())
+		}
+	}
+	return sshKeyIDs
+}
+
+const fqdnSuffixCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// generateFQDN combines the hostname prefix, the server prefix of the datacenter and a random suffix with the domain.
+func (r *Node) generateFQDN(data *NodeModel) error {
+	datacenter, err := findDatacenter(r.client, data.DatacenterID.ValueString())
+	if err != nil {
+		return err
+	}
+	suffix, err := randomString(fqdnSuffixCharset, 6)
+	if err != nil {
+		return err
+	}
+
+	labels := []string{data.HostPrefix.ValueString()}
+	if datacenter.ServerPrefix != "" {
+		labels = append(labels, strings.ToLower(datacenter.ServerPrefix))
+	}
+	labels = append(labels, suffix)
+	fqdn := strings.Join(labels, "-") + "." + data.Domain.ValueString()
+	if err := gpcloudvalidator.ValidateFQDN(fqdn); err != nil {
+		return fmt.Errorf("generated FQDN %s is invalid: %s", fqdn, err)
+	}
+	data.FQDN = types.StringValue(fqdn)
+	return nil
+}
+
+// getPassword returns the generated password or the password of the configuration, which is never hashed.
+func (nodeModel *NodeModel) getPassword(config *NodeModel) *string {
+	password := config.Password
+	if nodeModel.GeneratePwd.ValueBool() {
+		password = nodeModel.GeneratedPwd
+	}
+	if password.IsNull() || password.IsUnknown() {
+		return nil
+	}
+	passwd := password.ValueString()
+	return &passwd
+}
+
+func getStrings(list types.List) []string {
+	var values []string
+	for _, element := range list.Elements() {
+		if value, ok := element.(types.String); ok {
+			values = append(values, value.ValueString())
+		}
+	}
+	return values
+}
+
+func getPrimaryIP(node *cloudv1.Node) *string {
 	for _, networkInterface := range node.NetworkInterfaces {
 		for _, address := range networkInterface.IpAddresses {
 			ip := address
@@ -356,11 +1465,54 @@ func (nodeModel *NodeModel) write(node *cloudv1.Node) {
 	nodeModel.FlavourID = types.StringValue(node.Flavour.Id)
 	nodeModel.DatacenterID = types.StringValue(node.Datacenter.Id)
 	nodeModel.FQDN = types.StringValue(node.Fqdn)
-	nodeModel.BillingPeriod = types.StringValue(node.BillingPeriod.String())
+	// Keep aliases like monthly as configured. A requested billing period change might only take effect at the end
+	// of the current period, until then it is kept as pending instead of reverting billing_period to the active one.
+	billingPeriod := getBillingPeriod(nodeModel.BillingPeriod)
+	if billingPeriod == node.BillingPeriod {
+		nodeModel.PendingPeriod = types.StringNull()
+	} else if nodeModel.PendingPeriod.IsNull() || nodeModel.PendingPeriod.IsUnknown() || getBillingPeriod(nodeModel.PendingPeriod) != billingPeriod {
+		nodeModel.BillingPeriod = types.StringValue(node.BillingPeriod.String())
+		nodeModel.PendingPeriod = types.StringNull()
+	}
 	nodeModel.ImageID = types.StringValue(node.Image.Id)
 	nodeModel.Id = types.StringValue(node.Id)
 	nodeModel.Status = types.StringValue(node.Status.String())
-	if nodeIP := nodeModel.getPrimaryIP(node); nodeIP != nil {
+	if powerState := powerStateFromStatus(node.Status); powerState != "" {
+		nodeModel.PowerState = types.StringValue(powerState)
+	}
+	if nodeIP := getPrimaryIP(node); nodeIP != nil {
 		nodeModel.IP = types.StringValue(*nodeIP)
+		nodeModel.ConnHost = types.StringValue(*nodeIP)
+	}
+	// All images are installed with root as the login user
+	nodeModel.ConnUser = types.StringValue("root")
+	nodeModel.writeMetadata(node)
+}
+
+// fillMissing sets the attributes missing in the state of imported nodes and of nodes created by previous
+// versions of the provider, so a configuration matching the node plans without changes.
+func (nodeModel *NodeModel) fillMissing(node *cloudv1.Node) {
+	for _, value := range []*types.Bool{&nodeModel.GeneratePwd, &nodeModel.HashSecrets, &nodeModel.UserDataGzip, &nodeModel.Reinstall, &nodeModel.DestroyOnFail, &nodeModel.Protected} {
+		if value.IsNull() {
+			*value = types.BoolValue(false)
+		}
+	}
+	if nodeModel.PeriodGuard.IsNull() {
+		nodeModel.PeriodGuard = types.StringValue("off")
+	}
+	if nodeModel.Tags.IsNull() {
+		nodeModel.Tags = tagsValue(node.Tags)
+	}
+}
+
+// tagsValue returns the tags of the node, null if it has none.
+func tagsValue(nodeTags map[string]string) types.Map {
+	if len(nodeTags) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	tags := map[string]attr.Value{}
+	for key, value := range nodeTags {
+		tags[key] = types.StringValue(value)
 	}
+	return types.MapValueMust(types.StringType, tags)
 }
@@ -0,0 +1,345 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NodeAction{}
+var _ resource.ResourceWithValidateConfig = &NodeAction{}
+
+func NewNodeAction() resource.Resource {
+	return &NodeAction{}
+}
+
+// NodeAction defines the resource implementation.
+type NodeAction struct {
+	client *client.Client
+}
+
+// NodeActionModel describes the resource data model.
+type NodeActionModel struct {
+	NodeID    types.String `tfsdk:"node_id"`
+	ProjectID types.String `tfsdk:"project_id"`
+	Action    types.String `tfsdk:"action"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	Password  types.String `tfsdk:"password"`
+	SSHKeyIDs types.List   `tfsdk:"ssh_key_ids"`
+	UserData  types.String `tfsdk:"user_data"`
+	Status    types.String `tfsdk:"status"`
+	Id        types.String `tfsdk:"id"`
+}
+
+func (r *NodeAction) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_action"
+}
+
+func (r *NodeAction) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Node Action performs a one-shot operation (reboot, reset, reinstall, rescue_on, rescue_off) on an existing Node.\n\n" +
+			"The action is performed when the resource gets created and every time one of the `triggers` changes.\n" +
+			"Destroying the resource does not change the Node.\n\n" +
+			"`reinstall` installs the current image of the Node again with the given `password`, `ssh_key_ids` and `user_data`, " +
+			"the action completes once the reinstalled Node is running.\n",
+
+		Attributes: map[string]schema.Attribute{
+			"node_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Node to perform the action on",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID the Node is located in",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"action": schema.StringAttribute{
+				MarkdownDescription: "Action to perform (`reboot`, `reset`, `reinstall`, `rescue_on` or `rescue_off`)",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.NodeActionValidator{},
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values, changing any of them performs the action again",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password to reinstall the Node with, only used by `reinstall`. " +
+					"One of `password` or `ssh_key_ids` has to be set for `reinstall`",
+				Optional:  true,
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssh_key_ids": schema.ListAttribute{
+				MarkdownDescription: "SSH Keys to reinstall the Node with, only used by `reinstall`",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					gpcloudvalidator.UUIDListValidator{},
+				},
+			},
+			"user_data": schema.StringAttribute{
+				MarkdownDescription: "User Data for cloud-init to reinstall the Node with, only used by `reinstall`",
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UserDataValidator{},
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Node Status after the action was performed",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Node Action ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *NodeAction) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NodeAction) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *NodeActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Action.IsUnknown() {
+		return
+	}
+
+	if data.Action.ValueString() != "reinstall" {
+		for _, attribute := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"password", data.Password},
+			{"ssh_key_ids", data.SSHKeyIDs},
+			{"user_data", data.UserData},
+		} {
+			if !attribute.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(attribute.name), "Unused Attribute",
+					fmt.Sprintf("%s is only used by the reinstall action.", attribute.name))
+			}
+		}
+		return
+	}
+	// Without credentials the reinstalled node can not be logged into
+	if data.Password.IsNull() && data.SSHKeyIDs.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing Authentication",
+			"One of password or ssh_key_ids has to be set to reinstall the node.")
+	}
+}
+
+func (r *NodeAction) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *NodeActionModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeID := data.NodeID.ValueString()
+	projectID := data.ProjectID.ValueString()
+	expectedStatus := cloudv1.NodeStatus_NODE_STATUS_RUNNING
+
+	var err error
+	var reinstalled *cloudv1.Node
+	switch data.Action.ValueString() {
+	case "reboot":
+		_, err = r.client.CloudClient().PowerActionNode(context.Background(), &cloudv1.PowerActionNodeRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+			Action:    cloudv1.PowerAction_POWER_ACTION_REBOOT,
+		})
+	case "reset":
+		_, err = r.client.CloudClient().PowerActionNode(context.Background(), &cloudv1.PowerActionNodeRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+			Action:    cloudv1.PowerAction_POWER_ACTION_RESET,
+		})
+	case "reinstall":
+		// Reinstall with the image the node is currently installed with
+		var getNodeResponse *cloudv1.GetNodeResponse
+		getNodeResponse, err = r.client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+		})
+		if err != nil {
+			break
+		}
+		reinstallRequest := &cloudv1.ReinstallNodeRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+			ImageId:   getNodeResponse.Node.Image.Id,
+			SshKeyIds: getStrings(data.SSHKeyIDs),
+		}
+		if !data.Password.IsNull() {
+			password := data.Password.ValueString()
+			reinstallRequest.Password = &password
+		}
+		if !data.UserData.IsNull() {
+			userData := data.UserData.ValueString()
+			reinstallRequest.UserData = &userData
+		}
+		var reinstallResponse *cloudv1.ReinstallNodeResponse
+		reinstallResponse, err = r.client.CloudClient().ReinstallNode(context.Background(), reinstallRequest)
+		if err == nil {
+			reinstalled = reinstallResponse.Node
+		}
+	case "rescue_on":
+		expectedStatus = cloudv1.NodeStatus_NODE_STATUS_RESCUE
+		_, err = r.client.CloudClient().EnableNodeRescue(context.Background(), &cloudv1.EnableNodeRescueRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+		})
+	case "rescue_off":
+		_, err = r.client.CloudClient().DisableNodeRescue(context.Background(), &cloudv1.DisableNodeRescueRequest{
+			Id:        nodeID,
+			ProjectId: projectID,
+		})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to perform node action %s, got error: %s", data.Action.ValueString(), err))
+		return
+	}
+
+	var node *cloudv1.Node
+	switch {
+	case reinstalled != nil:
+		// The node still runs the old system right after the request, wait for the reinstall itself
+		node, err = waitForRestart(ctx, r.client, projectID, nodeID, reinstalled, "reinstalling", 30*time.Minute)
+		if err != nil {
+			resp.Diagnostics.AddError("Timeout Error", err.Error())
+			return
+		}
+	case data.Action.ValueString() == "reboot" || data.Action.ValueString() == "reset":
+		// The node is still running right after the request, it has to go down before it is seen as restarted
+		operation := "rebooting"
+		if data.Action.ValueString() == "reset" {
+			operation = "resetting"
+		}
+		node, err = waitForRestart(ctx, r.client, projectID, nodeID, nil, operation, 30*time.Minute)
+		if err != nil {
+			resp.Diagnostics.AddError("Timeout Error", err.Error())
+			return
+		}
+	default:
+		node, err = waitForNodeCondition(ctx, r.client, projectID, nodeID, nil, 30*time.Minute, func(node *cloudv1.Node) bool {
+			return node.Status == expectedStatus
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Timeout Error", fmt.Sprintf("Node did not settle after action %s: %s", data.Action.ValueString(), err))
+			return
+		}
+	}
+
+	data.Id = types.StringValue(uuid.NewString())
+	data.Status = types.StringValue(node.Status.String())
+
+	tflog.Trace(ctx, fmt.Sprintf("Performed node action %s on node: %s", data.Action.ValueString(), nodeID))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeAction) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *NodeActionModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Actions are events, there is no remote state to refresh
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only keeps the state, every attribute replaces the resource to perform the action again.
+func (r *NodeAction) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *NodeActionModel
+	var state *NodeActionModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = state.Id
+	data.Status = state.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeAction) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to do, removing the action from the state does not change the node
+}
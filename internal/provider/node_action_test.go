@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+func TestNodeActionChangeReplaces(t *testing.T) {
+	config := map[string]tftypes.Value{
+		"node_id":    tftypes.NewValue(tftypes.String, "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"),
+		"project_id": tftypes.NewValue(tftypes.String, "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"),
+		"action":     tftypes.NewValue(tftypes.String, "reboot"),
+		"triggers":   stringMap(map[string]string{"kernel": "6.1"}),
+	}
+	changes := map[string]tftypes.Value{
+		"node_id":  tftypes.NewValue(tftypes.String, "1b2c3d4e-5f60-4718-9a2b-3c4d5e6f7081"),
+		"action":   tftypes.NewValue(tftypes.String, "reset"),
+		"triggers": stringMap(map[string]string{"kernel": "6.2"}),
+	}
+	for name, value := range changes {
+		t.Run(name, func(t *testing.T) {
+			state := copyValues(config)
+			state["status"] = tftypes.NewValue(tftypes.String, "NODE_STATUS_RUNNING")
+			state["id"] = tftypes.NewValue(tftypes.String, "6d5c4b3a-2918-4706-8e5d-4c3b2a190817")
+			changedConfig := copyValues(config)
+			changedConfig[name] = value
+			proposedNewState := copyValues(state)
+			proposedNewState[name] = value
+
+			resp := planResource(t, "gpcloud_node_action", resourceType(t, &NodeAction{}), changedConfig, state, proposedNewState)
+			if len(resp.RequiresReplace) != 1 || !resp.RequiresReplace[0].Equal(tftypes.NewAttributePath().WithAttributeName(name)) {
+				t.Errorf("expected %s to replace the action, got %v", name, resp.RequiresReplace)
+			}
+		})
+	}
+}
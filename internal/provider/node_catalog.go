@@ -0,0 +1,106 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"strings"
+)
+
+// The lookups below resolve the human-readable names of datacenters, flavours and images
+// using the same list calls as the corresponding data sources.
+
+// findDatacenter returns the datacenter matching the short name (e.g. fra01), name or ID.
+func findDatacenter(client *client.Client, name string) (*cloudv1.Datacenter, error) {
+	datacenterList, err := client.CloudClient().ListDatacenters(context.Background(), &cloudv1.ListDatacentersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list datacenters: %s", err)
+	}
+	var available []string
+	for _, datacenter := range datacenterList.Datacenters {
+		if datacenter.Id == name || strings.EqualFold(datacenter.Short, name) || strings.EqualFold(datacenter.Name, name) {
+			return datacenter, nil
+		}
+		available = append(available, datacenter.Short)
+	}
+	return nil, fmt.Errorf("datacenter %s not found, available datacenters: %s", name, strings.Join(available, ", "))
+}
+
+// findFlavour returns the flavour matching the name (e.g. xeon.2288g.128) within the project and datacenter.
+func findFlavour(client *client.Client, projectID, datacenterID, name string) (*cloudv1.Flavour, error) {
+	flavours, err := listFlavours(client, projectID, datacenterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, flavour := range flavours {
+		if strings.EqualFold(flavour.Name, name) {
+			return flavour, nil
+		}
+	}
+	return nil, fmt.Errorf("flavour %s not found for project %s, available flavours: %s", name, projectID, flavourNames(flavours))
+}
+
+// listFlavours returns the flavours the project can order in the datacenter.
+func listFlavours(client *client.Client, projectID, datacenterID string) ([]*cloudv1.Flavour, error) {
+	flavourList, err := client.CloudClient().ListProjectFlavours(context.Background(), &cloudv1.ListProjectFlavoursRequest{
+		Id:           projectID,
+		DatacenterId: datacenterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list flavours: %s", err)
+	}
+	return flavourList.Flavours, nil
+}
+
+func flavourNames(flavours []*cloudv1.Flavour) string {
+	var names []string
+	for _, flavour := range flavours {
+		names = append(names, flavour.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// findImage returns the public image offered for the flavour or the project image matching the name (e.g. Ubuntu 22.04).
+func findImage(client *client.Client, projectID, flavourID, name string) (*cloudv1.Image, error) {
+	images, err := listImages(client, projectID, flavourID)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if image.Name == name {
+			return image, nil
+		}
+	}
+	return nil, fmt.Errorf("image %s not found for the flavour, available images: %s", name, imageNames(images))
+}
+
+func imageNames(images []*cloudv1.Image) string {
+	var names []string
+	for _, image := range images {
+		names = append(names, image.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// listImages returns the public images offered for the flavour followed by the images of the project.
+func listImages(client *client.Client, projectID, flavourID string) ([]*cloudv1.Image, error) {
+	var images []*cloudv1.Image
+	imageList, err := client.CloudClient().ListPublicImages(context.Background(), &cloudv1.ListPublicImagesRequest{
+		FlavourId: flavourID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list images: %s", err)
+	}
+	for _, os := range imageList.OperatingSystems {
+		images = append(images, os.Images...)
+	}
+
+	projectImageList, err := client.CloudClient().ListProjectImages(context.Background(), &cloudv1.ListProjectImagesRequest{
+		Id: projectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list project images: %s", err)
+	}
+	return append(images, projectImageList.Images...), nil
+}
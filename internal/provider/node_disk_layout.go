@@ -0,0 +1,274 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"strings"
+)
+
+var raidLevels = map[string]cloudv1.RaidLevel{
+	"none":   cloudv1.RaidLevel_RAID_LEVEL_NONE,
+	"raid0":  cloudv1.RaidLevel_RAID_LEVEL_RAID0,
+	"raid1":  cloudv1.RaidLevel_RAID_LEVEL_RAID1,
+	"raid5":  cloudv1.RaidLevel_RAID_LEVEL_RAID5,
+	"raid10": cloudv1.RaidLevel_RAID_LEVEL_RAID10,
+}
+
+// raidMinDisks is the number of disks required for the RAID level.
+var raidMinDisks = map[string]int{
+	"none":   1,
+	"raid0":  2,
+	"raid1":  2,
+	"raid5":  3,
+	"raid10": 4,
+}
+
+// DiskLayoutModel describes the disk_layout block.
+type DiskLayoutModel struct {
+	RaidLevel  types.String         `tfsdk:"raid_level"`
+	Partitions []DiskPartitionModel `tfsdk:"partition"`
+}
+
+type DiskPartitionModel struct {
+	MountPoint types.String `tfsdk:"mount_point"`
+	Filesystem types.String `tfsdk:"filesystem"`
+	SizeGB     types.Int64  `tfsdk:"size_gb"`
+}
+
+func diskLayoutBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Disk layout applied when the node is installed, instead of the default partitioning of the image. " +
+			"Changing it replaces the node, or reinstalls it if `reinstall_on_change` is set. All data on the disks is erased either way",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplaceIf(
+				diskLayoutRequiresReplace,
+				"Changing the disk layout replaces the node unless reinstall_on_change is set.",
+				"Changing the disk layout replaces the node unless `reinstall_on_change` is set.",
+			),
+		},
+		Attributes: map[string]schema.Attribute{
+			"raid_level": schema.StringAttribute{
+				MarkdownDescription: "Software RAID level spanning the disks of the flavour (`none`, `raid0`, `raid1`, `raid5` or `raid10`). " +
+					"With `none`, only the first disk is partitioned",
+				Optional: true,
+				Validators: []validator.String{
+					gpcloudvalidator.RaidLevelValidator{},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"partition": schema.ListNestedBlock{
+				MarkdownDescription: "Partitions in the order they are created",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"mount_point": schema.StringAttribute{
+							MarkdownDescription: "Absolute mount point (e.g. `/var`), not set for swap partitions",
+							Optional:            true,
+						},
+						"filesystem": schema.StringAttribute{
+							MarkdownDescription: "Filesystem of the partition (`ext4`, `xfs`, `btrfs` or `swap`)",
+							Required:            true,
+							Validators: []validator.String{
+								gpcloudvalidator.FilesystemValidator{},
+							},
+						},
+						"size_gb": schema.Int64Attribute{
+							MarkdownDescription: "Size of the partition in GB. Only the last partition may leave it unset to use the remaining space",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// diskLayoutRequiresReplace forces a replacement on disk layout changes unless the node should be reinstalled in place.
+func diskLayoutRequiresReplace(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	var reinstall types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("reinstall_on_change"), &reinstall)...)
+	resp.RequiresReplace = !reinstall.ValueBool()
+}
+
+// getDiskLayout returns the disk_layout block, nil if it is not set or not known yet.
+func (nodeModel *NodeModel) getDiskLayout(ctx context.Context) (*DiskLayoutModel, diag.Diagnostics) {
+	if nodeModel.DiskLayout.IsNull() || nodeModel.DiskLayout.IsUnknown() {
+		return nil, nil
+	}
+	// Partitions from dynamic blocks might not be known during plan, they can not be read into the model
+	if partitions, ok := nodeModel.DiskLayout.Attributes()["partition"].(types.List); ok {
+		if partitions.IsUnknown() {
+			return nil, nil
+		}
+		for _, partition := range partitions.Elements() {
+			if partition.IsUnknown() {
+				return nil, nil
+			}
+		}
+	}
+	var layout DiskLayoutModel
+	diags := nodeModel.DiskLayout.As(ctx, &layout, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+	return &layout, diags
+}
+
+func (layout *DiskLayoutModel) isKnown() bool {
+	if layout.RaidLevel.IsUnknown() {
+		return false
+	}
+	for _, partition := range layout.Partitions {
+		if partition.MountPoint.IsUnknown() || partition.Filesystem.IsUnknown() || partition.SizeGB.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+func (layout *DiskLayoutModel) getRaidLevel() string {
+	if layout.RaidLevel.IsNull() {
+		return "none"
+	}
+	return layout.RaidLevel.ValueString()
+}
+
+// validate checks the partitions and, if the flavour is given, that the layout fits onto its disks.
+func (layout *DiskLayoutModel) validate(flavour *cloudv1.Flavour) error {
+	if len(layout.Partitions) == 0 {
+		return fmt.Errorf("at least one partition has to be defined")
+	}
+
+	var totalSize int64
+	mountPoints := map[string]bool{}
+	for i, partition := range layout.Partitions {
+		if partition.Filesystem.ValueString() == "swap" {
+			if !partition.MountPoint.IsNull() {
+				return fmt.Errorf("swap partition %d must not have a mount point", i+1)
+			}
+		} else {
+			mountPoint := partition.MountPoint.ValueString()
+			if !strings.HasPrefix(mountPoint, "/") {
+				return fmt.Errorf("partition %d needs an absolute mount point", i+1)
+			}
+			if mountPoints[mountPoint] {
+				return fmt.Errorf("mount point %s is used by more than one partition", mountPoint)
+			}
+			mountPoints[mountPoint] = true
+		}
+
+		if partition.SizeGB.IsNull() {
+			if i != len(layout.Partitions)-1 {
+				return fmt.Errorf("only the last partition may leave size_gb unset")
+			}
+			continue
+		}
+		if partition.SizeGB.ValueInt64() <= 0 {
+			return fmt.Errorf("size_gb of partition %d has to be positive", i+1)
+		}
+		totalSize += partition.SizeGB.ValueInt64()
+	}
+	if !mountPoints["/"] {
+		return fmt.Errorf("a partition mounted at / is required")
+	}
+
+	if flavour == nil || len(flavour.Disks) == 0 {
+		return nil
+	}
+	raidLevel := layout.getRaidLevel()
+	if len(flavour.Disks) < raidMinDisks[raidLevel] {
+		return fmt.Errorf("%s requires at least %d disks, flavour %s has %d", raidLevel, raidMinDisks[raidLevel], flavour.Name, len(flavour.Disks))
+	}
+	if raidLevel == "raid10" && len(flavour.Disks)%2 != 0 {
+		return fmt.Errorf("raid10 requires an even number of disks, flavour %s has %d", flavour.Name, len(flavour.Disks))
+	}
+	if capacity := usableCapacity(raidLevel, flavour.Disks); totalSize > capacity {
+		return fmt.Errorf("the partitions need %d GB, but only %d GB are usable with %s on flavour %s", totalSize, capacity, raidLevel, flavour.Name)
+	}
+	return nil
+}
+
+// usableCapacity returns the space in GB available for partitions with the RAID level.
+func usableCapacity(raidLevel string, disks []*cloudv1.FlavourDisk) int64 {
+	smallest := int64(disks[0].SizeGb)
+	for _, disk := range disks {
+		if int64(disk.SizeGb) < smallest {
+			smallest = int64(disk.SizeGb)
+		}
+	}
+	count := int64(len(disks))
+	switch raidLevel {
+	case "raid0":
+		return count * smallest
+	case "raid1":
+		return smallest
+	case "raid5":
+		return (count - 1) * smallest
+	case "raid10":
+		return count / 2 * smallest
+	}
+	return int64(disks[0].SizeGb)
+}
+
+func (layout *DiskLayoutModel) toRequest() *cloudv1.DiskLayout {
+	diskLayout := &cloudv1.DiskLayout{
+		RaidLevel: raidLevels[layout.getRaidLevel()],
+	}
+	for _, partition := range layout.Partitions {
+		diskLayout.Partitions = append(diskLayout.Partitions, &cloudv1.DiskPartition{
+			MountPoint: partition.MountPoint.ValueString(),
+			Filesystem: partition.Filesystem.ValueString(),
+			SizeGb:     int32(partition.SizeGB.ValueInt64()),
+		})
+	}
+	return diskLayout
+}
+
+// validateDiskLayout checks the planned disk layout against the disks of the flavour and explains the consequences of changing it.
+func (r *Node) validateDiskLayout(ctx context.Context, plan *NodeModel, state *NodeModel, resp *diag.Diagnostics) {
+	if state != nil && !plan.DiskLayout.Equal(state.DiskLayout) {
+		if plan.Reinstall.ValueBool() {
+			resp.AddAttributeWarning(path.Root("disk_layout"), "Disk Layout Change",
+				fmt.Sprintf("Changing the disk layout reinstalls node %s, all data on its disks is erased.", state.FQDN.ValueString()))
+		} else {
+			resp.AddAttributeWarning(path.Root("disk_layout"), "Disk Layout Change",
+				fmt.Sprintf("Changing the disk layout replaces node %s, all data on its disks is lost.", state.FQDN.ValueString()))
+		}
+	}
+
+	layout, diags := plan.getDiskLayout(ctx)
+	resp.Append(diags...)
+	if layout == nil || !layout.isKnown() {
+		return
+	}
+	if state != nil && plan.DiskLayout.Equal(state.DiskLayout) && plan.FlavourID.Equal(state.FlavourID) {
+		return
+	}
+
+	var flavour *cloudv1.Flavour
+	if r.client != nil && !plan.ProjectID.IsUnknown() && !plan.DatacenterID.IsUnknown() && !plan.FlavourID.IsUnknown() {
+		flavours, err := listFlavours(r.client, plan.ProjectID.ValueString(), plan.DatacenterID.ValueString())
+		if err != nil {
+			resp.AddError("Client Error", fmt.Sprintf("Unable to validate disk layout, got error: %s", err))
+			return
+		}
+		for _, f := range flavours {
+			if f.Id == plan.FlavourID.ValueString() {
+				flavour = f
+			}
+		}
+	}
+	if err := layout.validate(flavour); err != nil {
+		resp.AddAttributeError(path.Root("disk_layout"), "Invalid Disk Layout", err.Error())
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestGetDiskLayoutUnknownPartitions(t *testing.T) {
+	layoutType := diskLayoutBlock().Type().(types.ObjectType)
+	partitionsType := layoutType.AttrTypes["partition"].(types.ListType)
+	partitionType := partitionsType.ElemType.(types.ObjectType)
+
+	knownPartition := types.ObjectValueMust(partitionType.AttrTypes, map[string]attr.Value{
+		"mount_point": types.StringValue("/"),
+		"filesystem":  types.StringValue("ext4"),
+		"size_gb":     types.Int64Unknown(),
+	})
+	tests := map[string]types.List{
+		"unknown list":    types.ListUnknown(partitionType),
+		"unknown element": types.ListValueMust(partitionType, []attr.Value{knownPartition, types.ObjectUnknown(partitionType.AttrTypes)}),
+	}
+	for name, partitions := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := &NodeModel{
+				DiskLayout: types.ObjectValueMust(layoutType.AttrTypes, map[string]attr.Value{
+					"raid_level": types.StringValue("raid1"),
+					"partition":  partitions,
+				}),
+			}
+			layout, diags := node.getDiskLayout(context.Background())
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if layout != nil {
+				t.Errorf("expected no layout while partitions are unknown, got %+v", layout)
+			}
+		})
+	}
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		node := &NodeModel{
+			DiskLayout: types.ObjectValueMust(layoutType.AttrTypes, map[string]attr.Value{
+				"raid_level": types.StringValue("raid1"),
+				"partition":  types.ListValueMust(partitionType, []attr.Value{knownPartition}),
+			}),
+		}
+		layout, diags := node.getDiskLayout(context.Background())
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if layout == nil || layout.isKnown() {
+			t.Errorf("expected a layout that is not fully known, got %+v", layout)
+		}
+	})
+}
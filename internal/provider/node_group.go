@@ -0,0 +1,691 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NodeGroup{}
+var _ resource.ResourceWithModifyPlan = &NodeGroup{}
+var _ resource.ResourceWithValidateConfig = &NodeGroup{}
+
+var nodeGroupNodeAttrTypes = map[string]attr.Type{
+	"id":     types.StringType,
+	"fqdn":   types.StringType,
+	"ip":     types.StringType,
+	"status": types.StringType,
+}
+
+func NewNodeGroup() resource.Resource {
+	return &NodeGroup{}
+}
+
+// NodeGroup defines the resource implementation.
+type NodeGroup struct {
+	client *client.Client
+}
+
+// NodeGroupModel describes the resource data model.
+type NodeGroupModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	FlavourID     types.String `tfsdk:"flavour_id"`
+	DatacenterID  types.String `tfsdk:"datacenter_id"`
+	Password      types.String `tfsdk:"password"`
+	SSHKeyIDs     types.List   `tfsdk:"ssh_key_ids"`
+	UserData      types.String `tfsdk:"user_data"`
+	FQDNs         types.List   `tfsdk:"fqdns"`
+	FQDNPattern   types.String `tfsdk:"fqdn_pattern"`
+	NodeCount     types.Int64  `tfsdk:"node_count"`
+	BillingPeriod types.String `tfsdk:"billing_period"`
+	ImageID       types.String `tfsdk:"image_id"`
+	Tags          types.Map    `tfsdk:"tags"`
+	Nodes         types.Map    `tfsdk:"nodes"`
+	Id            types.String `tfsdk:"id"`
+}
+
+// NodeGroupNodeModel describes a single node of the group.
+type NodeGroupNodeModel struct {
+	Id     types.String `tfsdk:"id"`
+	FQDN   types.String `tfsdk:"fqdn"`
+	IP     types.String `tfsdk:"ip"`
+	Status types.String `tfsdk:"status"`
+}
+
+func (r *NodeGroup) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_group"
+}
+
+func (r *NodeGroup) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Node Group provisions many identical Bare Metal Nodes with a single API call.\n\n" +
+			"The FQDNs are either given as a list using `fqdns` or generated from `fqdn_pattern` and `node_count`, " +
+			"where `%d` in the pattern is replaced with the node number starting at 1.\n" +
+			"Adding or removing FQDNs only creates or destroys the affected Nodes, changing the shared hardware or image configuration recreates the whole group.\n" +
+			"The password, SSH keys and user data are only used to install nodes, changing them only affects nodes created afterwards.\n" +
+			"Nodes that do not become ready in time are kept in the group and reported with a warning, their `status` shows their progress.\n\n",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID to create the nodes in",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"flavour_id": schema.StringAttribute{
+				MarkdownDescription: "Flavour ID used for all nodes",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"datacenter_id": schema.StringAttribute{
+				MarkdownDescription: "Datacenter ID the nodes are located in",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password used for authentication. Changes only apply to nodes created afterwards",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ssh_key_ids": schema.ListAttribute{
+				MarkdownDescription: "SSH Keys used for authentication. Changes only apply to nodes created afterwards",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.List{
+					gpcloudvalidator.UUIDListValidator{},
+				},
+			},
+			"user_data": schema.StringAttribute{
+				MarkdownDescription: "User Data to be provided for cloud-init. Changes only apply to nodes created afterwards",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					gpcloudvalidator.UserDataValidator{},
+				},
+			},
+			"fqdns": schema.ListAttribute{
+				MarkdownDescription: "Fully Qualified Domain Names of the nodes",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"fqdn_pattern": schema.StringAttribute{
+				MarkdownDescription: "Pattern to generate the FQDNs from, `%d` is replaced with the node number (example: `web%d.example.com`)",
+				Optional:            true,
+			},
+			"node_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of nodes to generate using `fqdn_pattern`, at least 1",
+				Optional:            true,
+			},
+			"billing_period": schema.StringAttribute{
+				MarkdownDescription: "Billing Configuration",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.BillingPeriodValidator{},
+				},
+			},
+			"image_id": schema.StringAttribute{
+				MarkdownDescription: "Image ID to install the nodes with (ID of gpcloud_image or gpcloud_project_image)",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Tags applied to all nodes",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"nodes": schema.MapNestedAttribute{
+				MarkdownDescription: "Nodes of the group, keyed by the configured FQDN",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Node ID",
+							Computed:            true,
+						},
+						"fqdn": schema.StringAttribute{
+							MarkdownDescription: "Fully Qualified Domain Name of the node as reported by the API",
+							Computed:            true,
+						},
+						"ip": schema.StringAttribute{
+							MarkdownDescription: "IP Address of the node",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Node Status",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Node Group ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *NodeGroup) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NodeGroup) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *NodeGroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.FQDNs.IsNull() && !data.FQDNPattern.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdns"), "Invalid Attribute Combination", "Only one of fqdns and fqdn_pattern can be set.")
+		return
+	}
+	if data.FQDNs.IsNull() && data.FQDNPattern.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdns"), "Missing Attribute", "Either fqdns or fqdn_pattern has to be set.")
+		return
+	}
+	if !data.FQDNPattern.IsNull() {
+		if data.NodeCount.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("node_count"), "Missing Attribute", "node_count is required when using fqdn_pattern.")
+		}
+		if !data.FQDNPattern.IsUnknown() && !strings.Contains(data.FQDNPattern.ValueString(), "%d") {
+			resp.Diagnostics.AddAttributeError(path.Root("fqdn_pattern"), "Invalid FQDN Pattern", "fqdn_pattern has to contain the %d placeholder.")
+		}
+		if !data.NodeCount.IsNull() && !data.NodeCount.IsUnknown() && data.NodeCount.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("node_count"), "Invalid Node Count", "node_count has to be at least 1.")
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fqdns, known := data.getFQDNs()
+	if !known {
+		return
+	}
+	if len(fqdns) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("fqdns"), "Missing Attribute", "fqdns has to contain at least one FQDN.")
+	}
+	// The API does not distinguish FQDNs by case or a trailing dot
+	seen := map[string]bool{}
+	for _, fqdn := range fqdns {
+		if seen[normalizeFQDN(fqdn)] {
+			resp.Diagnostics.AddAttributeError(path.Root("fqdns"), "Duplicate FQDN", fmt.Sprintf("FQDN %s is used for more than one node.", fqdn))
+		}
+		seen[normalizeFQDN(fqdn)] = true
+	}
+}
+
+func (r *NodeGroup) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *NodeGroupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Password.Equal(state.Password) || !plan.SSHKeyIDs.Equal(state.SSHKeyIDs) || !plan.UserData.Equal(state.UserData) {
+		resp.Diagnostics.AddWarning("Existing Nodes Not Changed",
+			"The password, SSH keys and user data are only used to install nodes. The existing nodes of the group keep the ones they got installed with, only nodes created afterwards use the new values.")
+	}
+
+	// Keep the known nodes in the plan as long as the group is neither scaled nor retagged
+	fqdns, known := plan.getFQDNs()
+	if !known || !plan.Tags.Equal(state.Tags) {
+		return
+	}
+	stateNodes := keyNodesByFQDN(state.getNodes(ctx), fqdns)
+	if len(fqdns) != len(stateNodes) {
+		return
+	}
+	for _, fqdn := range fqdns {
+		if _, ok := stateNodes[fqdn]; !ok {
+			return
+		}
+	}
+	resp.Diagnostics.Append(plan.setNodeModels(ctx, stateNodes)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("nodes"), plan.Nodes)...)
+}
+
+func (r *NodeGroup) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *NodeGroupModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fqdns, _ := data.getFQDNs()
+	data.Id = types.StringValue(uuid.NewString())
+
+	nodes, diags := r.createNodes(ctx, data, fqdns)
+	resp.Diagnostics.Append(diags...)
+	// Keep track of all nodes that got created, even if some of them did not become ready
+	resp.Diagnostics.Append(data.setNodes(ctx, nodes)...)
+
+	tflog.Trace(ctx, fmt.Sprintf("Created node group with ID: %s", data.Id.ValueString()))
+
+	if len(nodes) == 0 {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeGroup) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *NodeGroupModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodes := map[string]NodeGroupNodeModel{}
+	for fqdn, node := range data.getNodes(ctx) {
+		nodeResponse, err := r.client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
+			Id:        node.Id.ValueString(),
+			ProjectId: data.ProjectID.ValueString(),
+		})
+		if err != nil && status.Code(err) == codes.NotFound {
+			// Node got removed outside of terraform, it will be recreated on the next apply
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get node %s, got error: %s", fqdn, err))
+			return
+		}
+		nodes[fqdn] = newNodeGroupNodeModel(nodeResponse.Node)
+		// Tags changed outside of terraform or missing after a failed creation are applied again by the next apply
+		if !tagsEqual(data.Tags, nodeResponse.Node.Tags) {
+			data.Tags = tagsValue(nodeResponse.Node.Tags)
+		}
+	}
+
+	resp.Diagnostics.Append(data.setNodeModels(ctx, nodes)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeGroup) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state *NodeGroupModel
+
+	// Read Terraform plan and prior state data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fqdns, _ := data.getFQDNs()
+	nodes := keyNodesByFQDN(state.getNodes(ctx), fqdns)
+
+	// Destroy nodes that are no longer part of the group
+	wanted := map[string]bool{}
+	for _, fqdn := range fqdns {
+		wanted[fqdn] = true
+	}
+	for fqdn, node := range nodes {
+		if wanted[fqdn] {
+			continue
+		}
+		_, err := r.client.CloudClient().DestroyNode(context.Background(), &cloudv1.DestroyNodeRequest{
+			Id:        node.Id.ValueString(),
+			ProjectId: data.ProjectID.ValueString(),
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete node %s, got error: %s", fqdn, err))
+			break
+		}
+		delete(nodes, fqdn)
+	}
+
+	// Update tags of the remaining nodes
+	if !resp.Diagnostics.HasError() && !data.Tags.Equal(state.Tags) {
+		for fqdn, node := range nodes {
+			updatedNode, err := r.updateTags(data, node.Id.ValueString(), node.FQDN.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update node %s, got error: %s", fqdn, err))
+				break
+			}
+			nodes[fqdn] = newNodeGroupNodeModel(updatedNode)
+		}
+	}
+
+	// Create the nodes that are missing
+	var missing []string
+	for _, fqdn := range fqdns {
+		if _, ok := nodes[fqdn]; !ok {
+			missing = append(missing, fqdn)
+		}
+	}
+	if !resp.Diagnostics.HasError() && len(missing) > 0 {
+		createdNodes, diags := r.createNodes(ctx, data, missing)
+		resp.Diagnostics.Append(diags...)
+		for fqdn, node := range createdNodes {
+			nodes[fqdn] = newNodeGroupNodeModel(node)
+		}
+	}
+
+	// Always save the nodes that exist, so that a failed scaling operation does not lose track of them
+	resp.Diagnostics.Append(data.setNodeModels(ctx, nodes)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	tflog.Trace(ctx, fmt.Sprintf("Updated node group: %s", data.Id.ValueString()))
+}
+
+func (r *NodeGroup) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *NodeGroupModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for fqdn, node := range data.getNodes(ctx) {
+		_, err := r.client.CloudClient().DestroyNode(context.Background(), &cloudv1.DestroyNodeRequest{
+			Id:        node.Id.ValueString(),
+			ProjectId: data.ProjectID.ValueString(),
+		})
+		if err != nil && status.Code(err) == codes.NotFound {
+			resp.Diagnostics.AddWarning("Client Warning", fmt.Sprintf("Node %s that should be deleted does not exist: %s", fqdn, err))
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete node %s, got error: %s", fqdn, err))
+		}
+	}
+}
+
+// createNodes creates all given FQDNs with a single request, tags the nodes right away and waits for all of them concurrently.
+// The returned map contains all nodes that got created keyed by their configured FQDN. Nodes that did not become ready
+// or could not be tagged are kept and reported as warnings, so a partial failure does not taint the whole group.
+func (r *NodeGroup) createNodes(ctx context.Context, data *NodeGroupModel, fqdns []string) (map[string]*cloudv1.Node, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	createRequest := &cloudv1.CreateNodeRequest{
+		Fqdns:         fqdns,
+		ProjectId:     data.ProjectID.ValueString(),
+		FlavourId:     data.FlavourID.ValueString(),
+		DatacenterId:  data.DatacenterID.ValueString(),
+		ImageId:       data.ImageID.ValueString(),
+		BillingPeriod: getBillingPeriod(data.BillingPeriod),
+	}
+
+	if !data.Password.IsNull() {
+		passwd := data.Password.ValueString()
+		createRequest.Password = &passwd
+	}
+	for _, sshKeyID := range data.SSHKeyIDs.Elements() {
+		if sshKeyIDString, ok := sshKeyID.(types.String); ok {
+			createRequest.SshKeyIds = append(createRequest.SshKeyIds, sshKeyIDString.ValueString())
+		}
+	}
+	if !data.UserData.IsNull() {
+		userData := data.UserData.ValueString()
+		createRequest.UserData = &userData
+	}
+
+	createResponse, err := r.client.CloudClient().CreateNode(context.Background(), createRequest)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to create nodes, got error: %s", err))
+		return nil, diags
+	}
+
+	nodes := map[string]*cloudv1.Node{}
+	var notReady, untagged []string
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i, node := range createResponse.Nodes {
+		wg.Add(1)
+		go func(fqdn string, node *cloudv1.Node) {
+			defer wg.Done()
+			// Tags can not be passed with the create request, set them right away so tag based automation picks up the nodes early
+			tagged := true
+			if len(data.Tags.Elements()) > 0 {
+				taggedNode, err := tagNewNode(ctx, r.client, node.ProjectId, node.Id, node.Fqdn, data.Tags)
+				if err != nil {
+					tflog.Warn(ctx, fmt.Sprintf("Unable to tag node %s after creation: %s", fqdn, err))
+					tagged = false
+				} else {
+					node = taggedNode
+				}
+			}
+			readyNode, err := waitForNodeCondition(ctx, r.client, node.ProjectId, node.Id, node, 5*time.Minute, func(node *cloudv1.Node) bool {
+				return getPrimaryIP(node) != nil
+			})
+			if err != nil {
+				tflog.Warn(ctx, fmt.Sprintf("Node %s did not become ready: %s", fqdn, err))
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			nodes[fqdn] = readyNode
+			if err != nil {
+				notReady = append(notReady, fqdn)
+			}
+			if !tagged {
+				untagged = append(untagged, fqdn)
+			}
+		}(matchFQDN(fqdns, node, i), node)
+	}
+	wg.Wait()
+
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		diags.AddWarning("Nodes Not Ready",
+			fmt.Sprintf("Nodes %s did not get an IP address within 5 minutes. They are kept in the group, their status is refreshed on the next plan.", strings.Join(notReady, ", ")))
+	}
+	if len(untagged) > 0 {
+		sort.Strings(untagged)
+		diags.AddWarning("Nodes Not Tagged",
+			fmt.Sprintf("Unable to tag nodes %s after creation. The next plan detects the missing tags and applies them again.", strings.Join(untagged, ", ")))
+	}
+	return nodes, diags
+}
+
+// matchFQDN returns the requested FQDN of a created node. The API might normalize the FQDN, e.g. its case,
+// so the nodes are matched case insensitive and by their position in case that fails.
+func matchFQDN(fqdns []string, node *cloudv1.Node, index int) string {
+	for _, fqdn := range fqdns {
+		if normalizeFQDN(fqdn) == normalizeFQDN(node.Fqdn) {
+			return fqdn
+		}
+	}
+	if index < len(fqdns) {
+		return fqdns[index]
+	}
+	return node.Fqdn
+}
+
+// keyNodesByFQDN moves nodes keyed by an FQDN that only differs in its normalization from a configured one,
+// like nodes of previous versions of the provider keyed by the FQDN reported by the API, to the configured FQDN.
+func keyNodesByFQDN(nodes map[string]NodeGroupNodeModel, fqdns []string) map[string]NodeGroupNodeModel {
+	keyed := map[string]NodeGroupNodeModel{}
+	for key, node := range nodes {
+		keyed[key] = node
+	}
+	for _, fqdn := range fqdns {
+		if _, ok := keyed[fqdn]; ok {
+			continue
+		}
+		for key, node := range keyed {
+			if !slices.Contains(fqdns, key) && normalizeFQDN(key) == normalizeFQDN(fqdn) {
+				delete(keyed, key)
+				keyed[fqdn] = node
+				break
+			}
+		}
+	}
+	return keyed
+}
+
+func normalizeFQDN(fqdn string) string {
+	return strings.TrimSuffix(strings.ToLower(fqdn), ".")
+}
+
+func (r *NodeGroup) updateTags(data *NodeGroupModel, nodeID string, fqdn string) (*cloudv1.Node, error) {
+	updateRequest := &cloudv1.UpdateNodeRequest{
+		Id:        nodeID,
+		ProjectId: data.ProjectID.ValueString(),
+		Fqdn:      &fqdn,
+		Tags:      map[string]string{},
+	}
+	for s, value := range data.Tags.Elements() {
+		if stringValue, ok := value.(types.String); ok {
+			updateRequest.Tags[s] = stringValue.ValueString()
+		}
+	}
+	updateResponse, err := r.client.CloudClient().UpdateNode(context.Background(), updateRequest)
+	if err != nil {
+		return nil, err
+	}
+	return updateResponse.Node, nil
+}
+
+// tagsEqual reports whether the node got exactly the tags of the group.
+func tagsEqual(tags types.Map, nodeTags map[string]string) bool {
+	if len(tags.Elements()) != len(nodeTags) {
+		return false
+	}
+	for key, value := range tags.Elements() {
+		if stringValue, ok := value.(types.String); !ok || nodeTags[key] != stringValue.ValueString() {
+			return false
+		}
+	}
+	return true
+}
+
+// getFQDNs returns the configured FQDNs and whether they are known yet.
+func (nodeGroupModel *NodeGroupModel) getFQDNs() ([]string, bool) {
+	if nodeGroupModel.FQDNs.IsUnknown() || nodeGroupModel.FQDNPattern.IsUnknown() || nodeGroupModel.NodeCount.IsUnknown() {
+		return nil, false
+	}
+
+	var fqdns []string
+	if !nodeGroupModel.FQDNPattern.IsNull() {
+		for i := int64(1); i <= nodeGroupModel.NodeCount.ValueInt64(); i++ {
+			fqdns = append(fqdns, strings.ReplaceAll(nodeGroupModel.FQDNPattern.ValueString(), "%d", fmt.Sprint(i)))
+		}
+		return fqdns, true
+	}
+	for _, fqdn := range nodeGroupModel.FQDNs.Elements() {
+		if fqdn.IsUnknown() {
+			return nil, false
+		}
+		if fqdnString, ok := fqdn.(types.String); ok {
+			fqdns = append(fqdns, fqdnString.ValueString())
+		}
+	}
+	return fqdns, true
+}
+
+func (nodeGroupModel *NodeGroupModel) getNodes(ctx context.Context) map[string]NodeGroupNodeModel {
+	nodes := map[string]NodeGroupNodeModel{}
+	if nodeGroupModel.Nodes.IsNull() || nodeGroupModel.Nodes.IsUnknown() {
+		return nodes
+	}
+	nodeGroupModel.Nodes.ElementsAs(ctx, &nodes, false)
+	return nodes
+}
+
+func (nodeGroupModel *NodeGroupModel) setNodes(ctx context.Context, nodes map[string]*cloudv1.Node) diag.Diagnostics {
+	nodeModels := map[string]NodeGroupNodeModel{}
+	for fqdn, node := range nodes {
+		nodeModels[fqdn] = newNodeGroupNodeModel(node)
+	}
+	return nodeGroupModel.setNodeModels(ctx, nodeModels)
+}
+
+func (nodeGroupModel *NodeGroupModel) setNodeModels(ctx context.Context, nodes map[string]NodeGroupNodeModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	nodeGroupModel.Nodes, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: nodeGroupNodeAttrTypes}, nodes)
+	return diags
+}
+
+func newNodeGroupNodeModel(node *cloudv1.Node) NodeGroupNodeModel {
+	nodeModel := NodeGroupNodeModel{
+		Id:     types.StringValue(node.Id),
+		FQDN:   types.StringValue(node.Fqdn),
+		IP:     types.StringNull(),
+		Status: types.StringValue(node.Status.String()),
+	}
+	if nodeIP := getPrimaryIP(node); nodeIP != nil {
+		nodeModel.IP = types.StringValue(*nodeIP)
+	}
+	return nodeModel
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+func TestNodeGroupValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]tftypes.Value
+		expected string
+	}{
+		{"pattern", map[string]tftypes.Value{
+			"fqdn_pattern": tftypes.NewValue(tftypes.String, "web%d.example.com"),
+			"node_count":   tftypes.NewValue(tftypes.Number, 3),
+		}, ""},
+		{"zero nodes", map[string]tftypes.Value{
+			"fqdn_pattern": tftypes.NewValue(tftypes.String, "web%d.example.com"),
+			"node_count":   tftypes.NewValue(tftypes.Number, 0),
+		}, "Invalid Node Count"},
+		{"empty list", map[string]tftypes.Value{
+			"fqdns": stringList(),
+		}, "Missing Attribute"},
+		{"duplicate", map[string]tftypes.Value{
+			"fqdns": stringList("web1.example.com", "WEB1.example.com."),
+		}, "Duplicate FQDN"},
+	}
+
+	objectType := resourceType(t, &NodeGroup{})
+	server := providerserver.NewProtocol6(New("test")())()
+	if _, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatalf("unexpected schema error: %s", err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := map[string]tftypes.Value{
+				"project_id":     tftypes.NewValue(tftypes.String, "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"),
+				"flavour_id":     tftypes.NewValue(tftypes.String, "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d"),
+				"datacenter_id":  tftypes.NewValue(tftypes.String, "5c4b3a29-1807-4f6e-8d5c-4b3a29180706"),
+				"image_id":       tftypes.NewValue(tftypes.String, "7e6d5c4b-3a29-4180-9f6e-5d4c3b2a1908"),
+				"billing_period": tftypes.NewValue(tftypes.String, "monthly"),
+			}
+			for name, value := range test.config {
+				config[name] = value
+			}
+			resp, err := server.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{
+				TypeName: "gpcloud_node_group",
+				Config:   resourceValue(t, objectType, config),
+			})
+			if err != nil {
+				t.Fatalf("unexpected validation error: %s", err)
+			}
+			var summaries []string
+			for _, diagnostic := range resp.Diagnostics {
+				summaries = append(summaries, diagnostic.Summary)
+			}
+			if test.expected == "" && len(summaries) > 0 {
+				t.Errorf("expected no diagnostics, got %v", summaries)
+			}
+			if test.expected != "" && (len(summaries) != 1 || summaries[0] != test.expected) {
+				t.Errorf("expected diagnostic %s, got %v", test.expected, summaries)
+			}
+		})
+	}
+}
+
+func TestMatchFQDN(t *testing.T) {
+	fqdns := []string{"web1.example.com", "Web2.example.com"}
+	tests := []struct {
+		fqdn     string
+		index    int
+		expected string
+	}{
+		{"web1.example.com", 1, "web1.example.com"},
+		{"web2.example.com.", 0, "Web2.example.com"},
+		{"node-7f3a.example.com", 1, "Web2.example.com"},
+		{"node-7f3a.example.com", 2, "node-7f3a.example.com"},
+	}
+	for _, test := range tests {
+		if fqdn := matchFQDN(fqdns, &cloudv1.Node{Fqdn: test.fqdn}, test.index); fqdn != test.expected {
+			t.Errorf("expected node %s at %d to match %s, got %s", test.fqdn, test.index, test.expected, fqdn)
+		}
+	}
+}
+
+func TestKeyNodesByFQDN(t *testing.T) {
+	nodes := map[string]NodeGroupNodeModel{
+		"web1.example.com": {Id: types.StringValue("1")},
+		"web2.example.com": {Id: types.StringValue("2")},
+		"web3.example.com": {Id: types.StringValue("3")},
+	}
+	keyed := keyNodesByFQDN(nodes, []string{"web1.example.com", "Web2.example.com", "web4.example.com"})
+	if len(keyed) != 3 || keyed["web1.example.com"].Id.ValueString() != "1" || keyed["Web2.example.com"].Id.ValueString() != "2" ||
+		keyed["web3.example.com"].Id.ValueString() != "3" {
+		t.Errorf("unexpected nodes %v", keyed)
+	}
+	if len(nodes) != 3 {
+		t.Errorf("expected the nodes to be left unchanged, got %v", nodes)
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
+	"time"
+)
+
+var nodeStatusMessages = map[cloudv1.NodeStatus]string{
+	cloudv1.NodeStatus_NODE_STATUS_PROVISIONING: "The node is being provisioned",
+	cloudv1.NodeStatus_NODE_STATUS_INSTALLING:   "The image is being installed",
+	cloudv1.NodeStatus_NODE_STATUS_RUNNING:      "The node is running",
+	cloudv1.NodeStatus_NODE_STATUS_STOPPED:      "The node is powered off",
+	cloudv1.NodeStatus_NODE_STATUS_RESCUE:       "The node is booted into the rescue system",
+	cloudv1.NodeStatus_NODE_STATUS_ERROR:        "The node failed, contact the support if the error persists",
+}
+
+// writeMetadata maps the informational fields of the node to the computed attributes.
+func (nodeModel *NodeModel) writeMetadata(node *cloudv1.Node) {
+	nodeModel.CreatedAt = timestampValue(node.CreatedAt)
+	nodeModel.UpdatedAt = timestampValue(node.UpdatedAt)
+	nodeModel.FlavourName = types.StringValue(node.Flavour.Name)
+	nodeModel.Hardware = types.StringValue(getHardwareSummary(node.Flavour))
+	nodeModel.DCShort = types.StringValue(node.Datacenter.Short)
+	nodeModel.ImageName = types.StringValue(node.Image.Name)
+
+	nodeModel.PeriodEnd = types.StringNull()
+	if periodEnd := getBillingPeriodEnd(node, nodeModel.lastPeriodEnd(), time.Now()); periodEnd != nil {
+		nodeModel.PeriodEnd = types.StringValue(periodEnd.Format(time.RFC3339))
+	}
+
+	statusMessage, ok := nodeStatusMessages[node.Status]
+	if !ok {
+		statusMessage = "The node status is unknown"
+	}
+	nodeModel.StatusMessage = types.StringValue(statusMessage)
+}
+
+// getHardwareSummary describes the flavour hardware, e.g. Intel Xeon E-2288G (8 cores), 128 GB RAM, 2x 960 GB NVMe.
+func getHardwareSummary(flavour *cloudv1.Flavour) string {
+	var parts []string
+	if flavour.Cpu != "" {
+		parts = append(parts, fmt.Sprintf("%s (%d cores)", flavour.Cpu, flavour.CpuCores))
+	}
+	if flavour.MemoryGb > 0 {
+		parts = append(parts, fmt.Sprintf("%d GB RAM", flavour.MemoryGb))
+	}
+
+	// Group identical disks
+	var disks []string
+	diskCounts := map[string]int{}
+	for _, disk := range flavour.Disks {
+		description := fmt.Sprintf("%d GB %s", disk.SizeGb, disk.Type)
+		if diskCounts[description] == 0 {
+			disks = append(disks, description)
+		}
+		diskCounts[description]++
+	}
+	for _, disk := range disks {
+		parts = append(parts, fmt.Sprintf("%dx %s", diskCounts[disk], disk))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func timestampValue(timestamp *timestamppb.Timestamp) types.String {
+	if timestamp == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(timestamp.AsTime().Format(time.RFC3339))
+}
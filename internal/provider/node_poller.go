@@ -0,0 +1,232 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Intervals of the poller, variables to keep the tests short
+var (
+	nodePollerMinInterval = 5 * time.Second
+	nodePollerMaxInterval = 60 * time.Second
+)
+
+// nodePollerMissingListings is the number of consecutive listings a node has to be missing from before its waiters
+// are notified, as a single listing might not contain a node that got ordered a moment ago.
+const nodePollerMissingListings = 2
+
+var nodePollers = map[*client.Client]*nodePoller{}
+var nodePollersMutex sync.Mutex
+
+// nodePoller combines the status polling of all nodes that are waited on concurrently
+// into one periodic list call per project, instead of polling every node on its own.
+type nodePoller struct {
+	list     func(projectID string) ([]*cloudv1.Node, error)
+	mutex    sync.Mutex
+	projects map[string][]*nodeWaiter
+}
+
+// nodeWaiter receives the latest state of a single node whenever it changes.
+type nodeWaiter struct {
+	nodeID      string
+	fingerprint string
+	missing     int
+	updates     chan nodeUpdate
+}
+
+// nodeUpdate is either the latest state of the node or the error why there is none.
+type nodeUpdate struct {
+	node *cloudv1.Node
+	err  error
+}
+
+// getNodePoller returns the poller shared by all resources using the same client.
+func getNodePoller(client *client.Client) *nodePoller {
+	nodePollersMutex.Lock()
+	defer nodePollersMutex.Unlock()
+
+	poller, ok := nodePollers[client]
+	if !ok {
+		poller = newNodePoller(func(projectID string) ([]*cloudv1.Node, error) {
+			// The API returns all nodes of the project at once, the list is not paginated
+			listResponse, err := client.CloudClient().ListNodes(context.Background(), &cloudv1.ListNodesRequest{
+				ProjectId: projectID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return listResponse.Nodes, nil
+		})
+		nodePollers[client] = poller
+	}
+	return poller
+}
+
+func newNodePoller(list func(projectID string) ([]*cloudv1.Node, error)) *nodePoller {
+	return &nodePoller{
+		list:     list,
+		projects: map[string][]*nodeWaiter{},
+	}
+}
+
+// wait blocks until the condition is met for the node, the timeout is reached, the context is canceled
+// or the node does not exist anymore. It returns the last node seen and an error if the condition was not met.
+func (p *nodePoller) wait(ctx context.Context, projectID, nodeID string, node *cloudv1.Node, timeout time.Duration, condition func(node *cloudv1.Node) bool) (*cloudv1.Node, error) {
+	if node != nil && condition(node) {
+		return node, nil
+	}
+
+	waiter := p.register(projectID, nodeID)
+	defer p.unregister(projectID, waiter)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case update := <-waiter.updates:
+			if update.err != nil {
+				return node, update.err
+			}
+			node = update.node
+			if condition(node) {
+				return node, nil
+			}
+		case <-timer.C:
+			return node, fmt.Errorf("timed out after %s", timeout)
+		case <-ctx.Done():
+			return node, ctx.Err()
+		}
+	}
+}
+
+func (p *nodePoller) register(projectID, nodeID string) *nodeWaiter {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	waiter := &nodeWaiter{
+		nodeID:  nodeID,
+		updates: make(chan nodeUpdate, 1),
+	}
+	waiters, running := p.projects[projectID]
+	p.projects[projectID] = append(waiters, waiter)
+	if !running {
+		go p.poll(projectID)
+	}
+	return waiter
+}
+
+func (p *nodePoller) unregister(projectID string, waiter *nodeWaiter) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	waiters := p.projects[projectID]
+	for i, w := range waiters {
+		if w == waiter {
+			p.projects[projectID] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+}
+
+// poll lists the nodes of the project until nobody is waiting anymore. The interval grows
+// while none of the awaited nodes change and is reset as soon as one of them does.
+func (p *nodePoller) poll(projectID string) {
+	interval := nodePollerMinInterval
+	for {
+		time.Sleep(withJitter(interval))
+
+		p.mutex.Lock()
+		if len(p.projects[projectID]) == 0 {
+			delete(p.projects, projectID)
+			p.mutex.Unlock()
+			return
+		}
+		p.mutex.Unlock()
+
+		nodes, err := p.list(projectID)
+		if err != nil {
+			interval = nextPollInterval(interval)
+			continue
+		}
+
+		changed := false
+		p.mutex.Lock()
+		for _, waiter := range p.projects[projectID] {
+			found := false
+			for _, node := range nodes {
+				if waiter.nodeID == node.Id {
+					found = true
+					waiter.missing = 0
+					if waiter.notify(node) {
+						changed = true
+					}
+				}
+			}
+			if !found {
+				waiter.missing++
+				if waiter.missing == nodePollerMissingListings {
+					waiter.send(nodeUpdate{err: fmt.Errorf("node %s not found in project %s", waiter.nodeID, projectID)})
+				}
+			}
+		}
+		p.mutex.Unlock()
+
+		if changed {
+			interval = nodePollerMinInterval
+		} else {
+			interval = nextPollInterval(interval)
+		}
+	}
+}
+
+// notify passes the node to the waiter in case it changed since the last notification.
+func (w *nodeWaiter) notify(node *cloudv1.Node) bool {
+	fingerprint := nodeFingerprint(node)
+	if fingerprint == w.fingerprint {
+		return false
+	}
+	w.fingerprint = fingerprint
+	w.send(nodeUpdate{node: node})
+	return true
+}
+
+func (w *nodeWaiter) send(update nodeUpdate) {
+	// Only the latest state is of interest, drop an update that was not picked up yet
+	select {
+	case <-w.updates:
+	default:
+	}
+	w.updates <- update
+}
+
+// nodeFingerprint summarizes the parts of a node that are relevant while waiting for it.
+func nodeFingerprint(node *cloudv1.Node) string {
+	nodeIP := ""
+	if ip := getPrimaryIP(node); ip != nil {
+		nodeIP = *ip
+	}
+	imageID := ""
+	if node.Image != nil {
+		imageID = node.Image.Id
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", node.Status.String(), nodeIP, imageID, node.Fqdn)
+}
+
+func nextPollInterval(interval time.Duration) time.Duration {
+	interval = interval * 3 / 2
+	if interval > nodePollerMaxInterval {
+		return nodePollerMaxInterval
+	}
+	return interval
+}
+
+// withJitter spreads the interval by +/- 20% to avoid all pollers hitting the API at the same time.
+func withJitter(interval time.Duration) time.Duration {
+	spread := int64(interval) / 5
+	return interval - time.Duration(spread) + time.Duration(rand.Int63n(2*spread+1))
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"errors"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNodeList serves the nodes of a project to a poller and counts the list calls.
+type fakeNodeList struct {
+	mutex sync.Mutex
+	nodes map[string]*cloudv1.Node
+	calls int
+}
+
+func (l *fakeNodeList) list(projectID string) ([]*cloudv1.Node, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.calls++
+	nodes := []*cloudv1.Node{}
+	for _, node := range l.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (l *fakeNodeList) set(node *cloudv1.Node) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.nodes[node.Id] = node
+}
+
+func (l *fakeNodeList) remove(nodeID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.nodes, nodeID)
+}
+
+// newTestNodePoller returns a poller with short intervals. The intervals are restored once the poller stopped.
+func newTestNodePoller(t *testing.T, nodes *fakeNodeList) *nodePoller {
+	minInterval, maxInterval := nodePollerMinInterval, nodePollerMaxInterval
+	nodePollerMinInterval, nodePollerMaxInterval = time.Millisecond, 5*time.Millisecond
+	poller := newNodePoller(nodes.list)
+	t.Cleanup(func() {
+		for !poller.stopped() {
+			time.Sleep(time.Millisecond)
+		}
+		nodePollerMinInterval, nodePollerMaxInterval = minInterval, maxInterval
+	})
+	return poller
+}
+
+func (p *nodePoller) stopped() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.projects) == 0
+}
+
+func nodeRunning(node *cloudv1.Node) bool {
+	return node.Status == cloudv1.NodeStatus_NODE_STATUS_RUNNING
+}
+
+func TestNodePollerNotifiesAllWaiters(t *testing.T) {
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{}}
+	nodeIDs := []string{"node-1", "node-2", "node-3"}
+	for _, nodeID := range nodeIDs {
+		nodes.set(&cloudv1.Node{Id: nodeID, Status: cloudv1.NodeStatus_NODE_STATUS_PROVISIONING})
+	}
+	poller := newTestNodePoller(t, nodes)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(nodeIDs)*2)
+	for _, nodeID := range nodeIDs {
+		// Two waiters on the same node have to be notified both
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(nodeID string) {
+				defer wg.Done()
+				node, err := poller.wait(context.Background(), "project", nodeID, nil, 5*time.Second, nodeRunning)
+				if err == nil && node.Id != nodeID {
+					err = errors.New("got node " + node.Id + ", expected " + nodeID)
+				}
+				errs <- err
+			}(nodeID)
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	for _, nodeID := range nodeIDs {
+		nodes.set(&cloudv1.Node{Id: nodeID, Status: cloudv1.NodeStatus_NODE_STATUS_RUNNING})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// The poller stops once nobody is waiting anymore
+	time.Sleep(20 * time.Millisecond)
+	if !poller.stopped() {
+		t.Error("poller still running without waiters")
+	}
+}
+
+func TestNodePollerConditionAlreadyMet(t *testing.T) {
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{}}
+	poller := newTestNodePoller(t, nodes)
+
+	node := &cloudv1.Node{Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_RUNNING}
+	got, err := poller.wait(context.Background(), "project", "node-1", node, time.Second, nodeRunning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != node {
+		t.Errorf("got %v, expected the given node", got)
+	}
+	if nodes.calls != 0 {
+		t.Errorf("got %d list calls, expected none", nodes.calls)
+	}
+}
+
+func TestNodePollerContextCanceled(t *testing.T) {
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{
+		"node-1": {Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_PROVISIONING},
+	}}
+	poller := newTestNodePoller(t, nodes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	node, err := poller.wait(ctx, "project", "node-1", nil, time.Minute, nodeRunning)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, expected %v", err, context.Canceled)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("wait returned after %s, expected it to return on cancellation", time.Since(start))
+	}
+	if node == nil || node.Status != cloudv1.NodeStatus_NODE_STATUS_PROVISIONING {
+		t.Errorf("got node %v, expected the last node seen", node)
+	}
+}
+
+func TestNodePollerTimeout(t *testing.T) {
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{
+		"node-1": {Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_PROVISIONING},
+	}}
+	poller := newTestNodePoller(t, nodes)
+
+	_, err := poller.wait(context.Background(), "project", "node-1", nil, 20*time.Millisecond, nodeRunning)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("got error %v, expected a timeout", err)
+	}
+}
+
+func TestNodePollerNodeNotFound(t *testing.T) {
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{
+		"node-1": {Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_PROVISIONING},
+	}}
+	poller := newTestNodePoller(t, nodes)
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		nodes.remove("node-1")
+	})
+	start := time.Now()
+	node, err := poller.wait(context.Background(), "project", "node-1", nil, time.Minute, nodeRunning)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("got error %v, expected the node not to be found", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("wait returned after %s, expected it to return once the node is missing", time.Since(start))
+	}
+	if node == nil || node.Id != "node-1" {
+		t.Errorf("got node %v, expected the last node seen", node)
+	}
+}
+
+func TestNextPollInterval(t *testing.T) {
+	interval := nodePollerMinInterval
+	for i := 0; i < 20; i++ {
+		next := nextPollInterval(interval)
+		if next > nodePollerMaxInterval {
+			t.Fatalf("interval %s exceeds the maximum %s", next, nodePollerMaxInterval)
+		}
+		if interval < nodePollerMaxInterval && next <= interval {
+			t.Fatalf("interval did not grow from %s, got %s", interval, next)
+		}
+		interval = next
+	}
+	if interval != nodePollerMaxInterval {
+		t.Errorf("got interval %s, expected it to be capped at %s", interval, nodePollerMaxInterval)
+	}
+	if got := nextPollInterval(10 * time.Second); got != 15*time.Second {
+		t.Errorf("got interval %s, expected %s", got, 15*time.Second)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	interval := 10 * time.Second
+	for i := 0; i < 1000; i++ {
+		got := withJitter(interval)
+		if got < 8*time.Second || got > 12*time.Second {
+			t.Fatalf("got %s, expected %s +/- 20%%", got, interval)
+		}
+	}
+}
+
+func TestWaitForRestart(t *testing.T) {
+	networkInterfaces := []*cloudv1.NetworkInterface{{IpAddresses: []string{"192.0.2.10"}}}
+	nodes := &fakeNodeList{nodes: map[string]*cloudv1.Node{
+		"node-1": {Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_RUNNING, NetworkInterfaces: networkInterfaces},
+	}}
+	gpcloudClient := &client.Client{}
+	nodePollersMutex.Lock()
+	nodePollers[gpcloudClient] = newTestNodePoller(t, nodes)
+	nodePollersMutex.Unlock()
+	t.Cleanup(func() {
+		nodePollersMutex.Lock()
+		defer nodePollersMutex.Unlock()
+		delete(nodePollers, gpcloudClient)
+	})
+
+	restarted := &cloudv1.Node{Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_RUNNING, NetworkInterfaces: networkInterfaces}
+	time.AfterFunc(20*time.Millisecond, func() {
+		nodes.set(&cloudv1.Node{Id: "node-1", Status: cloudv1.NodeStatus_NODE_STATUS_STOPPED})
+		time.AfterFunc(20*time.Millisecond, func() {
+			nodes.set(restarted)
+		})
+	})
+	node, err := waitForRestart(context.Background(), gpcloudClient, "project", "node-1", nil, "rebooting", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The node running before the reboot must not be taken as restarted
+	if node != restarted {
+		t.Errorf("got node %v, expected the restarted node", node)
+	}
+}
@@ -0,0 +1,326 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"strconv"
+	"time"
+)
+
+const defaultRescueTimeout = 15 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NodeRescue{}
+
+func NewNodeRescue() resource.Resource {
+	return &NodeRescue{}
+}
+
+// NodeRescue defines the resource implementation.
+type NodeRescue struct {
+	client *client.Client
+}
+
+// NodeRescueModel describes the resource data model.
+type NodeRescueModel struct {
+	NodeID    types.String `tfsdk:"node_id"`
+	ProjectID types.String `tfsdk:"project_id"`
+	SSHKeyIDs types.List   `tfsdk:"ssh_key_ids"`
+	Password  types.String `tfsdk:"password"`
+	Timeout   types.String `tfsdk:"timeout"`
+	Host      types.String `tfsdk:"host"`
+	User      types.String `tfsdk:"user"`
+	Status    types.String `tfsdk:"status"`
+	Id        types.String `tfsdk:"id"`
+}
+
+func (r *NodeRescue) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_rescue"
+}
+
+func (r *NodeRescue) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Node Rescue boots an existing Node into the rescue system for as long as the resource exists.\n\n" +
+			"Creating the resource enables rescue mode and waits until SSH of the rescue system is reachable. " +
+			"Destroying it disables rescue mode and boots the Node from its disks again. " +
+			"If rescue mode is disabled outside of terraform and the Node runs from its disks or is powered off, the next plan enables it again.\n",
+
+		Attributes: map[string]schema.Attribute{
+			"node_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Node to boot into the rescue system",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID the Node is located in",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"ssh_key_ids": schema.ListAttribute{
+				MarkdownDescription: "SSH Keys authorized to log in to the rescue system",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					gpcloudvalidator.UUIDListValidator{},
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Root password of the rescue system, a strong password is generated if not set",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					// The generated password is kept first, so only configured password changes replace the resource
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait for the rescue system to become reachable (e.g. `20m`), defaults to 15 minutes",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.DurationValidator{},
+				},
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Address to connect to the rescue system with SSH",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "User to log in to the rescue system with",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Node Status",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Node Rescue ID, the ID of the Node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *NodeRescue) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NodeRescue) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *NodeRescueModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := defaultRescueTimeout
+	if !data.Timeout.IsNull() {
+		parsed, err := time.ParseDuration(data.Timeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Timeout", fmt.Sprintf("Unable to parse timeout: %s", err))
+			return
+		}
+		timeout = parsed
+	}
+	deadline := time.Now().Add(timeout)
+
+	if data.Password.IsUnknown() || data.Password.IsNull() {
+		password, err := generatePassword()
+		if err != nil {
+			resp.Diagnostics.AddError("Password Error", fmt.Sprintf("Unable to generate rescue password, got error: %s", err))
+			return
+		}
+		data.Password = types.StringValue(password)
+	}
+	password := data.Password.ValueString()
+
+	nodeID := data.NodeID.ValueString()
+	projectID := data.ProjectID.ValueString()
+	_, err := r.client.CloudClient().EnableNodeRescue(context.Background(), &cloudv1.EnableNodeRescueRequest{
+		Id:        nodeID,
+		ProjectId: projectID,
+		Password:  &password,
+		SshKeyIds: getStrings(data.SSHKeyIDs),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable rescue mode, got error: %s", err))
+		return
+	}
+
+	node, err := waitForNodeCondition(ctx, r.client, projectID, nodeID, nil, time.Until(deadline), func(node *cloudv1.Node) bool {
+		return node.Status == cloudv1.NodeStatus_NODE_STATUS_RESCUE
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Timeout Error", fmt.Sprintf("Node %s did not boot into the rescue system within %s: %s", nodeID, timeout, err))
+		return
+	}
+	nodeIP := getPrimaryIP(node)
+	if nodeIP == nil {
+		resp.Diagnostics.AddError("Rescue Error", fmt.Sprintf("Node %s has no IP address to reach the rescue system", nodeID))
+		return
+	}
+	if err := waitForSSHBanner(net.JoinHostPort(*nodeIP, strconv.Itoa(defaultSSHPort)), time.Until(deadline)); err != nil {
+		resp.Diagnostics.AddError("Timeout Error", fmt.Sprintf("Rescue system of node %s not reachable: %s", nodeID, err))
+		return
+	}
+
+	data.Id = types.StringValue(nodeID)
+	data.write(node)
+
+	tflog.Trace(ctx, fmt.Sprintf("Enabled rescue mode on node: %s", nodeID))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRescue) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *NodeRescueModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeResponse, err := r.client.CloudClient().GetNode(context.Background(), &cloudv1.GetNodeRequest{
+		Id:        data.NodeID.ValueString(),
+		ProjectId: data.ProjectID.ValueString(),
+	})
+	if err != nil && status.Code(err) == codes.NotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read node, got error: %s", err))
+		return
+	}
+
+	// Rescue mode was disabled outside of terraform. Transitional states like a reboot within the rescue system are kept.
+	if nodeResponse.Node.Status == cloudv1.NodeStatus_NODE_STATUS_RUNNING || nodeResponse.Node.Status == cloudv1.NodeStatus_NODE_STATUS_STOPPED {
+		tflog.Info(ctx, fmt.Sprintf("Node %s is not in rescue mode anymore (%s)", data.NodeID.ValueString(), nodeResponse.Node.Status))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.write(nodeResponse.Node)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRescue) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *NodeRescueModel
+	var state *NodeRescueModel
+
+	// Only the timeout can change without a replacement
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Status = state.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRescue) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *NodeRescueModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeID := data.NodeID.ValueString()
+	projectID := data.ProjectID.ValueString()
+	_, err := r.client.CloudClient().DisableNodeRescue(context.Background(), &cloudv1.DisableNodeRescueRequest{
+		Id:        nodeID,
+		ProjectId: projectID,
+	})
+	if err != nil && status.Code(err) == codes.NotFound {
+		resp.Diagnostics.AddWarning("Client Warning", fmt.Sprintf("Node to disable rescue mode on does not exist: %s", err))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable rescue mode, got error: %s", err))
+		return
+	}
+
+	// Give the node some time to leave the rescue system before polling
+	time.Sleep(time.Second * 10)
+	_, err = waitForNodeCondition(ctx, r.client, projectID, nodeID, nil, 30*time.Minute, func(node *cloudv1.Node) bool {
+		return node.Status == cloudv1.NodeStatus_NODE_STATUS_RUNNING
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Timeout Error", fmt.Sprintf("Node %s did not boot from its disks after disabling rescue mode: %s", nodeID, err))
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Disabled rescue mode on node: %s", nodeID))
+}
+
+func (data *NodeRescueModel) write(node *cloudv1.Node) {
+	data.Status = types.StringValue(node.Status.String())
+	if nodeIP := getPrimaryIP(node); nodeIP != nil {
+		data.Host = types.StringValue(*nodeIP)
+	}
+	// The rescue system is always entered as root
+	data.User = types.StringValue("root")
+}
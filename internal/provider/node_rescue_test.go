@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+func TestNodeRescueTimeoutChangeKeepsPassword(t *testing.T) {
+	objectType := resourceType(t, &NodeRescue{})
+	config := map[string]tftypes.Value{
+		"node_id":    tftypes.NewValue(tftypes.String, "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"),
+		"project_id": tftypes.NewValue(tftypes.String, "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"),
+		"timeout":    tftypes.NewValue(tftypes.String, "20m"),
+	}
+	state := map[string]tftypes.Value{
+		"node_id":    config["node_id"],
+		"project_id": config["project_id"],
+		"password":   tftypes.NewValue(tftypes.String, "generated-password"),
+		"host":       tftypes.NewValue(tftypes.String, "192.0.2.10"),
+		"user":       tftypes.NewValue(tftypes.String, "root"),
+		"status":     tftypes.NewValue(tftypes.String, "NODE_STATUS_RESCUE"),
+		"id":         config["node_id"],
+	}
+	proposedNewState := copyValues(state)
+	proposedNewState["timeout"] = config["timeout"]
+
+	resp := planResource(t, "gpcloud_node_rescue", objectType, config, state, proposedNewState)
+	if len(resp.RequiresReplace) > 0 {
+		t.Errorf("expected no replacement, got %v", resp.RequiresReplace)
+	}
+	if password := plannedAttributes(t, objectType, resp)["password"]; !password.Equal(state["password"]) {
+		t.Errorf("expected the generated password to be kept, got %s", password)
+	}
+}
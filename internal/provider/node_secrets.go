@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+	"strings"
+)
+
+const generatedPasswordLength = 32
+
+var generatedPasswordCharsets = []string{
+	"abcdefghijkmnopqrstuvwxyz",
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"23456789",
+	"!#%+-.:=?@_",
+}
+
+// secretHashModifier replaces the planned value with its SHA-256 when hash_secrets is set,
+// so only the hash is kept in state while changes of the configured value still show up in the plan.
+type secretHashModifier struct {
+}
+
+func (m secretHashModifier) Description(ctx context.Context) string {
+	return "Stores the SHA-256 of the value instead of the value itself if hash_secrets is set."
+}
+
+func (m secretHashModifier) MarkdownDescription(ctx context.Context) string {
+	return "Stores the SHA-256 of the value instead of the value itself if `hash_secrets` is set."
+}
+
+func (m secretHashModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// The attribute is computed to allow the hash in the plan, it must not fall back to the previous value
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		resp.PlanValue = req.ConfigValue
+		return
+	}
+
+	var hashSecrets types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("hash_secrets"), &hashSecrets)...)
+	if hashSecrets.IsUnknown() {
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+	if hashSecrets.ValueBool() {
+		resp.PlanValue = types.StringValue(hashSecret(req.ConfigValue.ValueString()))
+		return
+	}
+	resp.PlanValue = req.ConfigValue
+}
+
+func hashSecret(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// generatePassword creates a random password containing characters of every charset.
+func generatePassword() (string, error) {
+	alphabet := strings.Join(generatedPasswordCharsets, "")
+	for {
+		password, err := randomString(alphabet, generatedPasswordLength)
+		if err != nil {
+			return "", err
+		}
+
+		complete := true
+		for _, charset := range generatedPasswordCharsets {
+			if !strings.ContainsAny(password, charset) {
+				complete = false
+			}
+		}
+		if complete {
+			return password, nil
+		}
+	}
+}
+
+// randomString returns a cryptographically random string of the given length using characters of the alphabet.
+func randomString(alphabet string, length int) (string, error) {
+	value := make([]byte, length)
+	for i := range value {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		value[i] = alphabet[index.Int64()]
+	}
+	return string(value), nil
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSSHPort        = 22
+	defaultSSHWaitTimeout = 10 * time.Minute
+)
+
+// Timeouts of a single connection attempt, variables to keep the tests short
+var (
+	sshDialTimeout   = 5 * time.Second
+	sshRetryInterval = 5 * time.Second
+)
+
+// WaitForSSHModel describes the wait_for_ssh block.
+type WaitForSSHModel struct {
+	Port    types.Int64  `tfsdk:"port"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func waitForSSHBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Wait until SSH is reachable on the primary IP address before the node is reported as created or reinstalled",
+		Attributes: map[string]schema.Attribute{
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "SSH port, defaults to 22",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time to wait for SSH (e.g. `15m`), defaults to 10 minutes",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.DurationValidator{},
+				},
+			},
+		},
+	}
+}
+
+// waitForSSH waits for SSH on the primary IP address if the wait_for_ssh block is set.
+func (nodeModel *NodeModel) waitForSSH(ctx context.Context) error {
+	if nodeModel.WaitForSSH.IsNull() || nodeModel.WaitForSSH.IsUnknown() {
+		return nil
+	}
+	var config WaitForSSHModel
+	if diags := nodeModel.WaitForSSH.As(ctx, &config, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("unable to read wait_for_ssh")
+	}
+
+	port := int64(defaultSSHPort)
+	if !config.Port.IsNull() {
+		port = config.Port.ValueInt64()
+	}
+	timeout := defaultSSHWaitTimeout
+	if !config.Timeout.IsNull() {
+		parsed, err := time.ParseDuration(config.Timeout.ValueString())
+		if err != nil {
+			return fmt.Errorf("invalid wait_for_ssh timeout: %s", err)
+		}
+		timeout = parsed
+	}
+	return waitForSSHBanner(net.JoinHostPort(nodeModel.IP.ValueString(), strconv.FormatInt(port, 10)), timeout)
+}
+
+// waitForSSHBanner connects to the address until a SSH server answers with its banner or the timeout is reached.
+func waitForSSHBanner(address string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := readSSHBanner(address)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(sshRetryInterval).After(deadline) {
+			return fmt.Errorf("SSH on %s not reachable within %s, last error: %s", address, timeout, err)
+		}
+		time.Sleep(sshRetryInterval)
+	}
+}
+
+func readSSHBanner(address string) error {
+	conn, err := net.DialTimeout("tcp", address, sshDialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(time.Now().Add(sshDialTimeout)); err != nil {
+		return err
+	}
+	// Servers may send other lines before the identification string (RFC 4253, section 4.2)
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.HasPrefix(line, "SSH-") {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("no SSH banner received: %s", err)
+		}
+	}
+}
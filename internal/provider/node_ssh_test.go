@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveSSH accepts connections on a local port and handles them with the given function until the test ends.
+func serveSSH(t *testing.T, handle func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func shortSSHTimeouts(t *testing.T) {
+	dialTimeout, retryInterval := sshDialTimeout, sshRetryInterval
+	sshDialTimeout, sshRetryInterval = 200*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() {
+		sshDialTimeout, sshRetryInterval = dialTimeout, retryInterval
+	})
+}
+
+func TestWaitForSSHBanner(t *testing.T) {
+	shortSSHTimeouts(t)
+	address := serveSSH(t, func(conn net.Conn) {
+		// Lines before the identification string are allowed
+		conn.Write([]byte("Welcome\r\nSSH-2.0-OpenSSH_9.3\r\n"))
+	})
+
+	if err := waitForSSHBanner(address, time.Second); err != nil {
+		t.Errorf("expected SSH banner to be found, got error: %s", err)
+	}
+}
+
+func TestWaitForSSHBannerNonSSHPeer(t *testing.T) {
+	shortSSHTimeouts(t)
+	address := serveSSH(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\n\r\n"))
+	})
+
+	err := waitForSSHBanner(address, 300*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no SSH banner received") {
+		t.Errorf("expected missing SSH banner error, got: %v", err)
+	}
+}
+
+func TestWaitForSSHBannerSilentPeer(t *testing.T) {
+	shortSSHTimeouts(t)
+	address := serveSSH(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+	})
+
+	err := waitForSSHBanner(address, 300*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no SSH banner received") {
+		t.Errorf("expected missing SSH banner error, got: %v", err)
+	}
+}
+
+func TestWaitForSSHBannerTimeout(t *testing.T) {
+	shortSSHTimeouts(t)
+	// Nothing listens on the port anymore once the listener got closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	start := time.Now()
+	err = waitForSSHBanner(address, 300*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not reachable within 300ms") {
+		t.Errorf("expected timeout error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up after the timeout, took %s", elapsed)
+	}
+}
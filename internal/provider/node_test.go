@@ -0,0 +1,421 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"encoding/base64"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resourceType returns the terraform type of the resource.
+func resourceType(t *testing.T, r resource.Resource) tftypes.Object {
+	var resp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+}
+
+// nodeType returns the terraform type of the node resource.
+func nodeType(t *testing.T) tftypes.Object {
+	return resourceType(t, &Node{})
+}
+
+// resourceValue returns an object with the given attributes, all other attributes are null.
+func resourceValue(t *testing.T, objectType tftypes.Object, values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	value, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, attributes))
+	if err != nil {
+		t.Fatalf("unable to create resource value: %s", err)
+	}
+	return &value
+}
+
+// nodeValue returns a node object with the given attributes, all other attributes are null.
+func nodeValue(t *testing.T, values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	return resourceValue(t, nodeType(t), values)
+}
+
+// planResource runs the plan of the resource without a configured client and fails on error diagnostics.
+func planResource(t *testing.T, typeName string, objectType tftypes.Object, config, priorState, proposedNewState map[string]tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	resp := planResourceChange(t, typeName, objectType, config, priorState, proposedNewState)
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected plan diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	return resp
+}
+
+// planResourceChange runs the plan of the resource without a configured client.
+func planResourceChange(t *testing.T, typeName string, objectType tftypes.Object, config, priorState, proposedNewState map[string]tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	server := providerserver.NewProtocol6(New("test")())()
+	// Resource types are only registered with the provider type name after the schema got requested
+	if _, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatalf("unexpected schema error: %s", err)
+	}
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		Config:           resourceValue(t, objectType, config),
+		PriorState:       resourceValue(t, objectType, priorState),
+		ProposedNewState: resourceValue(t, objectType, proposedNewState),
+	})
+	if err != nil {
+		t.Fatalf("unexpected plan error: %s", err)
+	}
+	return resp
+}
+
+// planNode runs the plan of the node resource without a configured client, so names are not resolved again.
+func planNode(t *testing.T, config, priorState, proposedNewState map[string]tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	return planResource(t, "gpcloud_node", nodeType(t), config, priorState, proposedNewState)
+}
+
+func stringList(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, tftypes.NewValue(tftypes.String, value))
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+}
+
+func stringMap(values map[string]string) tftypes.Value {
+	elements := map[string]tftypes.Value{}
+	for key, value := range values {
+		elements[key] = tftypes.NewValue(tftypes.String, value)
+	}
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+}
+
+// existingNode returns the configuration and the matching state of a node that got created with the flavour,
+// datacenter and image names resolved.
+func existingNode() (map[string]tftypes.Value, map[string]tftypes.Value) {
+	config := map[string]tftypes.Value{
+		"project_id":     tftypes.NewValue(tftypes.String, "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"),
+		"flavours":       stringList("xeon.2288g.128", "epyc.7313p.128"),
+		"datacenters":    stringList("fra01", "ams01"),
+		"image":          tftypes.NewValue(tftypes.String, "Ubuntu 22.04"),
+		"billing_period": tftypes.NewValue(tftypes.String, "monthly"),
+		"fqdn":           tftypes.NewValue(tftypes.String, "web-01.example.com"),
+		"tags":           stringMap(map[string]string{"role": "web"}),
+	}
+	state := map[string]tftypes.Value{
+		"flavour_id":           tftypes.NewValue(tftypes.String, "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d"),
+		"datacenter_id":        tftypes.NewValue(tftypes.String, "5c4b3a29-1807-4f6e-8d5c-4b3a29180706"),
+		"image_id":             tftypes.NewValue(tftypes.String, "7e6d5c4b-3a29-4180-9f6e-5d4c3b2a1908"),
+		"generate_password":    tftypes.NewValue(tftypes.Bool, false),
+		"hash_secrets":         tftypes.NewValue(tftypes.Bool, false),
+		"user_data_gzip":       tftypes.NewValue(tftypes.Bool, false),
+		"reinstall_on_change":  tftypes.NewValue(tftypes.Bool, false),
+		"destroy_on_failure":   tftypes.NewValue(tftypes.Bool, false),
+		"deletion_protection":  tftypes.NewValue(tftypes.Bool, false),
+		"billing_period_guard": tftypes.NewValue(tftypes.String, "off"),
+		"power_state":          tftypes.NewValue(tftypes.String, "on"),
+		"status":               tftypes.NewValue(tftypes.String, "NODE_STATUS_RUNNING"),
+		"flavour_name":         tftypes.NewValue(tftypes.String, "xeon.2288g.128"),
+		"datacenter_short":     tftypes.NewValue(tftypes.String, "fra01"),
+		"image_name":           tftypes.NewValue(tftypes.String, "Ubuntu 22.04"),
+		"ip":                   tftypes.NewValue(tftypes.String, "192.0.2.10"),
+		"id":                   tftypes.NewValue(tftypes.String, "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"),
+	}
+	for name, value := range config {
+		state[name] = value
+	}
+	return config, state
+}
+
+// plannedAttributes returns the attributes of the planned state.
+func plannedAttributes(t *testing.T, objectType tftypes.Object, resp *tfprotov6.PlanResourceChangeResponse) map[string]tftypes.Value {
+	planned, err := resp.PlannedState.Unmarshal(objectType)
+	if err != nil {
+		t.Fatalf("unable to read planned state: %s", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := planned.As(&attributes); err != nil {
+		t.Fatalf("unable to read planned attributes: %s", err)
+	}
+	return attributes
+}
+
+func copyValues(values map[string]tftypes.Value) map[string]tftypes.Value {
+	copied := map[string]tftypes.Value{}
+	for name, value := range values {
+		copied[name] = value
+	}
+	return copied
+}
+
+func TestNodeTagChangeKeepsResolvedIDs(t *testing.T) {
+	config, state := existingNode()
+	config["tags"] = stringMap(map[string]string{"role": "db"})
+	proposedNewState := copyValues(state)
+	proposedNewState["tags"] = config["tags"]
+
+	resp := planNode(t, config, state, proposedNewState)
+	if len(resp.RequiresReplace) > 0 {
+		t.Errorf("expected no replacement, got %v", resp.RequiresReplace)
+	}
+
+	attributes := plannedAttributes(t, nodeType(t), resp)
+	for _, name := range []string{"flavour_id", "datacenter_id", "image_id", "flavour_name", "datacenter_short", "image_name", "ip"} {
+		if !attributes[name].Equal(state[name]) {
+			t.Errorf("expected %s to be kept as %s, got %s", name, state[name], attributes[name])
+		}
+	}
+}
+
+func TestNodeGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   tftypes.Value
+		state    tftypes.Value
+		replaced bool
+	}{
+		{"null state", tftypes.NewValue(tftypes.Bool, nil), tftypes.NewValue(tftypes.Bool, nil), false},
+		{"null state configured false", tftypes.NewValue(tftypes.Bool, false), tftypes.NewValue(tftypes.Bool, nil), false},
+		{"null state configured true", tftypes.NewValue(tftypes.Bool, true), tftypes.NewValue(tftypes.Bool, nil), true},
+		{"enabled", tftypes.NewValue(tftypes.Bool, true), tftypes.NewValue(tftypes.Bool, false), true},
+		{"disabled", tftypes.NewValue(tftypes.Bool, nil), tftypes.NewValue(tftypes.Bool, true), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, state := existingNode()
+			config["generate_password"] = test.config
+			state["generate_password"] = test.state
+			proposedNewState := copyValues(state)
+			if !test.config.IsNull() {
+				proposedNewState["generate_password"] = test.config
+			}
+
+			resp := planNode(t, config, state, proposedNewState)
+			if replaced := len(resp.RequiresReplace) > 0; replaced != test.replaced {
+				t.Errorf("expected replacement %t, got %v", test.replaced, resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestImportedNodePlansNoChanges(t *testing.T) {
+	const projectID = "9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"
+	node := &cloudv1.Node{
+		Id:            "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d",
+		ProjectId:     projectID,
+		Fqdn:          "web-01.example.com",
+		Flavour:       &cloudv1.Flavour{Id: "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d", Name: "xeon.2288g.128"},
+		Datacenter:    &cloudv1.Datacenter{Id: "5c4b3a29-1807-4f6e-8d5c-4b3a29180706", Short: "fra01"},
+		Image:         &cloudv1.Image{Id: "7e6d5c4b-3a29-4180-9f6e-5d4c3b2a1908", Name: "Ubuntu 22.04"},
+		BillingPeriod: cloudv1.BillingPeriod_BILLING_PERIOD_MONTHLY,
+		Status:        cloudv1.NodeStatus_NODE_STATUS_RUNNING,
+		Tags:          map[string]string{"role": "web"},
+		CreatedAt:     timestamppb.New(time.Date(2023, time.January, 31, 12, 0, 0, 0, time.UTC)),
+	}
+
+	// The state after terraform import, which only sets the ID and project before reading the node
+	var schemaResp resource.SchemaResponse
+	(&Node{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	imported, err := nodeValue(t, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, node.Id),
+		"project_id": tftypes.NewValue(tftypes.String, projectID),
+	}).Unmarshal(nodeType(t))
+	if err != nil {
+		t.Fatalf("unable to read imported state: %s", err)
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: imported}
+	var data *NodeModel
+	if diags := state.Get(context.Background(), &data); diags.HasError() {
+		t.Fatalf("unable to read imported state: %v", diags)
+	}
+	data.write(node)
+	data.fillMissing(node)
+	if diags := state.Set(context.Background(), &data); diags.HasError() {
+		t.Fatalf("unable to write read state: %v", diags)
+	}
+	var priorState map[string]tftypes.Value
+	if err := state.Raw.As(&priorState); err != nil {
+		t.Fatalf("unable to read state attributes: %s", err)
+	}
+
+	// The configuration generated by the discover command
+	config := map[string]tftypes.Value{
+		"project_id":     tftypes.NewValue(tftypes.String, projectID),
+		"fqdn":           tftypes.NewValue(tftypes.String, node.Fqdn),
+		"flavour_id":     tftypes.NewValue(tftypes.String, node.Flavour.Id),
+		"datacenter_id":  tftypes.NewValue(tftypes.String, node.Datacenter.Id),
+		"image_id":       tftypes.NewValue(tftypes.String, node.Image.Id),
+		"billing_period": tftypes.NewValue(tftypes.String, node.BillingPeriod.String()),
+		"tags":           stringMap(node.Tags),
+	}
+	proposedNewState := copyValues(priorState)
+	for name, value := range config {
+		proposedNewState[name] = value
+	}
+
+	resp := planNode(t, config, priorState, proposedNewState)
+	if len(resp.RequiresReplace) > 0 {
+		t.Errorf("expected no replacement, got %v", resp.RequiresReplace)
+	}
+	attributes := plannedAttributes(t, nodeType(t), resp)
+	for name, value := range priorState {
+		if !attributes[name].Equal(value) {
+			t.Errorf("expected %s to be kept as %s, got %s", name, value, attributes[name])
+		}
+	}
+}
+
+func TestNodeCloudConfigSize(t *testing.T) {
+	// Random content does not compress, repeated content does
+	random := make([]byte, userDataMaxSize)
+	rand.New(rand.NewSource(1)).Read(random)
+	tests := []struct {
+		name     string
+		content  string
+		gzip     bool
+		unknown  bool
+		expected bool
+	}{
+		{"small", "hello", false, false, false},
+		{"too large", strings.Repeat("a", userDataMaxSize), false, false, true},
+		{"compressed", strings.Repeat("a", userDataMaxSize), true, false, false},
+		{"too large compressed", base64.StdEncoding.EncodeToString(random), true, false, true},
+		{"unknown", "", false, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cloudConfigType := nodeType(t).AttributeTypes["cloud_config"].(tftypes.Object)
+			fileType := cloudConfigType.AttributeTypes["write_files"].(tftypes.List).ElementType.(tftypes.Object)
+			content := tftypes.NewValue(tftypes.String, test.content)
+			if test.unknown {
+				content = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+			}
+			cloudConfig := map[string]tftypes.Value{}
+			for name, attributeType := range cloudConfigType.AttributeTypes {
+				cloudConfig[name] = tftypes.NewValue(attributeType, nil)
+			}
+			cloudConfig["write_files"] = tftypes.NewValue(tftypes.List{ElementType: fileType}, []tftypes.Value{
+				tftypes.NewValue(fileType, map[string]tftypes.Value{
+					"path":        tftypes.NewValue(tftypes.String, "/etc/motd"),
+					"content":     content,
+					"permissions": tftypes.NewValue(tftypes.String, nil),
+					"owner":       tftypes.NewValue(tftypes.String, nil),
+				}),
+			})
+
+			config, state := existingNode()
+			config["cloud_config"] = tftypes.NewValue(cloudConfigType, cloudConfig)
+			config["user_data_gzip"] = tftypes.NewValue(tftypes.Bool, test.gzip)
+			proposedNewState := copyValues(state)
+			proposedNewState["cloud_config"] = config["cloud_config"]
+			proposedNewState["user_data_gzip"] = config["user_data_gzip"]
+
+			resp := planResourceChange(t, "gpcloud_node", nodeType(t), config, state, proposedNewState)
+			summaries := []string{}
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					summaries = append(summaries, diagnostic.Summary+": "+diagnostic.Detail)
+				}
+			}
+			if (len(summaries) > 0) != test.expected {
+				t.Errorf("expected size error %t, got %v", test.expected, summaries)
+			}
+		})
+	}
+}
+
+func TestValidateFlavour(t *testing.T) {
+	flavours := []*cloudv1.Flavour{
+		{Id: "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d", Name: "xeon.2288g.128", Available: true},
+		{Id: "8b7a6c5d-4e3f-4a2b-9c1d-0e8f7a6b5c4d", Name: "epyc.7313p.256", Available: false},
+	}
+	tests := []struct {
+		name     string
+		flavour  string
+		expected string
+	}{
+		{"available", "2a1f0c9e-6b3d-4e7a-8c5f-1d0e9b8a7c6d", ""},
+		{"out of stock", "8b7a6c5d-4e3f-4a2b-9c1d-0e8f7a6b5c4d", "Flavour Not Available"},
+		{"not orderable", "3c2b1a09-8f7e-4d6c-b5a4-9382716f5e4d", "Flavour Not Orderable"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan := &NodeModel{
+				ProjectID:    types.StringValue("9f4c2a3e-1b7d-4c8e-9a0f-3d2b1c4e5f60"),
+				DatacenterID: types.StringValue("5c4b3a29-1807-4f6e-8d5c-4b3a29180706"),
+				FlavourID:    types.StringValue(test.flavour),
+			}
+			var diags diag.Diagnostics
+			validateFlavour(plan, flavours, &diags)
+			summary := ""
+			if diags.HasError() {
+				summary = diags.Errors()[0].Summary()
+			}
+			if summary != test.expected {
+				t.Errorf("expected error %q, got %q", test.expected, summary)
+			}
+		})
+	}
+}
+
+func TestGetBillingPeriodEnd(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	monthly, yearly := cloudv1.BillingPeriod_BILLING_PERIOD_MONTHLY, cloudv1.BillingPeriod_BILLING_PERIOD_YEARLY
+	tests := []struct {
+		name          string
+		billingPeriod cloudv1.BillingPeriod
+		createdAt     time.Time
+		lastPeriodEnd *time.Time
+		now           time.Time
+		expected      *time.Time
+	}{
+		{"hourly", cloudv1.BillingPeriod_BILLING_PERIOD_HOURLY, date(2023, time.January, 31), nil, date(2023, time.February, 1), nil},
+		{"monthly first period", monthly, date(2023, time.January, 15), nil, date(2023, time.January, 16), timePointer(date(2023, time.February, 15))},
+		{"monthly end of january", monthly, date(2023, time.January, 31), nil, date(2023, time.February, 1), timePointer(date(2023, time.February, 28))},
+		{"monthly end of january in leap year", monthly, date(2024, time.January, 31), nil, date(2024, time.February, 1), timePointer(date(2024, time.February, 29))},
+		{"monthly after february", monthly, date(2023, time.January, 31), nil, date(2023, time.March, 1), timePointer(date(2023, time.March, 31))},
+		{"monthly after short month", monthly, date(2023, time.January, 31), nil, date(2023, time.April, 15), timePointer(date(2023, time.April, 30))},
+		{"monthly at period end", monthly, date(2023, time.January, 31), nil, date(2023, time.July, 31), timePointer(date(2023, time.August, 31))},
+		{"monthly end of year", monthly, date(2023, time.October, 31), nil, date(2023, time.December, 31), timePointer(date(2024, time.January, 31))},
+		{"yearly", yearly, date(2023, time.January, 31), nil, date(2025, time.March, 1), timePointer(date(2026, time.January, 31))},
+		{"yearly from leap day", yearly, date(2024, time.February, 29), nil, date(2024, time.March, 1), timePointer(date(2025, time.February, 28))},
+		{"yearly from leap day to leap year", yearly, date(2024, time.February, 29), nil, date(2027, time.March, 1), timePointer(date(2028, time.February, 29))},
+		// A billing period change takes effect at the end of the running period
+		{"changed period running", yearly, date(2023, time.January, 31), timePointer(date(2023, time.February, 28)), date(2023, time.February, 10), timePointer(date(2023, time.February, 28))},
+		{"changed to yearly", yearly, date(2023, time.January, 31), timePointer(date(2023, time.February, 28)), date(2023, time.March, 10), timePointer(date(2024, time.February, 28))},
+		{"changed to monthly", monthly, date(2023, time.January, 31), timePointer(date(2024, time.January, 31)), date(2024, time.March, 10), timePointer(date(2024, time.March, 31))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			periodEnd := getBillingPeriodEnd(&cloudv1.Node{
+				BillingPeriod: test.billingPeriod,
+				CreatedAt:     timestamppb.New(test.createdAt),
+			}, test.lastPeriodEnd, test.now)
+			if (periodEnd == nil) != (test.expected == nil) || (periodEnd != nil && !periodEnd.Equal(*test.expected)) {
+				t.Errorf("expected period end %v, got %v", test.expected, periodEnd)
+			}
+		})
+	}
+}
+
+func timePointer(value time.Time) *time.Time {
+	return &value
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"gopkg.in/yaml.v3"
+)
+
+// userDataMaxSize is the maximum size of the user data passed to the node, after compression.
+const userDataMaxSize = 64 * 1024
+
+// CloudConfigModel describes the cloud_config attribute, which is rendered to a #cloud-config document.
+type CloudConfigModel struct {
+	PackageUpdate     *bool                  `tfsdk:"package_update" yaml:"package_update,omitempty"`
+	PackageUpgrade    *bool                  `tfsdk:"package_upgrade" yaml:"package_upgrade,omitempty"`
+	Packages          []string               `tfsdk:"packages" yaml:"packages,omitempty"`
+	SSHAuthorizedKeys []string               `tfsdk:"ssh_authorized_keys" yaml:"ssh_authorized_keys,omitempty"`
+	WriteFiles        []CloudConfigFileModel `tfsdk:"write_files" yaml:"write_files,omitempty"`
+	RunCmd            []string               `tfsdk:"runcmd" yaml:"runcmd,omitempty"`
+}
+
+type CloudConfigFileModel struct {
+	Path        string  `tfsdk:"path" yaml:"path"`
+	Content     string  `tfsdk:"content" yaml:"content"`
+	Permissions *string `tfsdk:"permissions" yaml:"permissions,omitempty"`
+	Owner       *string `tfsdk:"owner" yaml:"owner,omitempty"`
+}
+
+func cloudConfigAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Cloud-init configuration rendered to a `#cloud-config` document by the provider, alternative to `user_data`. " +
+			"With `hash_secrets` the SHA-256 of the rendered document is stored as `user_data`, the values of `cloud_config` are still stored as configured",
+		Optional:  true,
+		Sensitive: true,
+		Attributes: map[string]schema.Attribute{
+			"package_update": schema.BoolAttribute{
+				MarkdownDescription: "Update the package database on first boot",
+				Optional:            true,
+			},
+			"package_upgrade": schema.BoolAttribute{
+				MarkdownDescription: "Upgrade all packages on first boot",
+				Optional:            true,
+			},
+			"packages": schema.ListAttribute{
+				MarkdownDescription: "Packages to install on first boot",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"ssh_authorized_keys": schema.ListAttribute{
+				MarkdownDescription: "Additional SSH public keys to authorize for the default user",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"write_files": schema.ListNestedAttribute{
+				MarkdownDescription: "Files to write on first boot",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							MarkdownDescription: "Absolute path of the file",
+							Required:            true,
+						},
+						"content": schema.StringAttribute{
+							MarkdownDescription: "Content of the file",
+							Required:            true,
+						},
+						"permissions": schema.StringAttribute{
+							MarkdownDescription: "Octal file permissions (e.g. `0644`)",
+							Optional:            true,
+						},
+						"owner": schema.StringAttribute{
+							MarkdownDescription: "Owner of the file (e.g. `root:root`)",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"runcmd": schema.ListAttribute{
+				MarkdownDescription: "Commands to run on first boot",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+// planCloudConfig checks the size of the rendered cloud_config once all of its values are known, which might only be the
+// case during plan. With hash_secrets it plans the SHA-256 of the rendered document as user_data, so changes of the
+// rendered document are detected the same way as changes of user_data.
+func (nodeModel *NodeModel) planCloudConfig(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if nodeModel.CloudConfig.IsNull() {
+		return diags
+	}
+	value, err := nodeModel.CloudConfig.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddAttributeError(path.Root("cloud_config"), "Invalid Cloud Config", fmt.Sprintf("Unable to read cloud_config, got error: %s", err))
+		return diags
+	}
+	if !value.IsFullyKnown() {
+		if nodeModel.HashSecrets.ValueBool() {
+			nodeModel.UserData = types.StringUnknown()
+		}
+		return diags
+	}
+
+	var cloudConfig CloudConfigModel
+	diags.Append(nodeModel.CloudConfig.As(ctx, &cloudConfig, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+	rendered, err := renderCloudConfig(cloudConfig)
+	if err != nil {
+		diags.AddAttributeError(path.Root("cloud_config"), "Invalid Cloud Config", fmt.Sprintf("Unable to render cloud_config, got error: %s", err))
+		return diags
+	}
+	if !nodeModel.UserDataGzip.IsUnknown() {
+		if _, err := encodeUserData(rendered, nodeModel.UserDataGzip.ValueBool()); err != nil {
+			diags.AddAttributeError(path.Root("cloud_config"), "Invalid Cloud Config", fmt.Sprintf("Rendered cloud_config is too large, got error: %s", err))
+			return diags
+		}
+	}
+	if nodeModel.HashSecrets.ValueBool() {
+		nodeModel.UserData = types.StringValue(hashSecret(rendered))
+	}
+	return diags
+}
+
+// getUserData returns the user data to pass to the node, rendered from cloud_config and compressed when requested.
+func (nodeModel *NodeModel) getUserData(ctx context.Context) (*string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var userData string
+	switch {
+	case !nodeModel.CloudConfig.IsNull():
+		var cloudConfig CloudConfigModel
+		diags.Append(nodeModel.CloudConfig.As(ctx, &cloudConfig, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		rendered, err := renderCloudConfig(cloudConfig)
+		if err != nil {
+			diags.AddAttributeError(path.Root("cloud_config"), "Invalid Cloud Config", fmt.Sprintf("Unable to render cloud_config, got error: %s", err))
+			return nil, diags
+		}
+		userData = rendered
+	case !nodeModel.UserData.IsNull():
+		userData = nodeModel.UserData.ValueString()
+	default:
+		return nil, diags
+	}
+
+	encoded, err := encodeUserData(userData, nodeModel.UserDataGzip.ValueBool())
+	if err != nil {
+		diags.AddError("Invalid User Data", fmt.Sprintf("Unable to encode user data, got error: %s", err))
+		return nil, diags
+	}
+	return &encoded, diags
+}
+
+func renderCloudConfig(cloudConfig CloudConfigModel) (string, error) {
+	rendered, err := yaml.Marshal(cloudConfig)
+	if err != nil {
+		return "", err
+	}
+	return "#cloud-config\n" + string(rendered), nil
+}
+
+// encodeUserData gzips and base64 encodes the user data if requested and checks the resulting size.
+func encodeUserData(userData string, compress bool) (string, error) {
+	if compress {
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write([]byte(userData)); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		userData = base64.StdEncoding.EncodeToString(buffer.Bytes())
+	}
+	if len(userData) > userDataMaxSize {
+		hint := ""
+		if !compress {
+			hint = ", consider setting user_data_gzip"
+		}
+		return "", fmt.Errorf("user data is %d bytes, exceeding the limit of %d bytes%s", len(userData), userDataMaxSize, hint)
+	}
+	return userData, nil
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
+	"context"
+	"fmt"
+	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var _ datasource.DataSource = &NodesDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &NodesDataSource{}
+
+func NewNodesDS() datasource.DataSource {
+	return &NodesDataSource{}
+}
+
+// NodesDataSource defines the data source implementation.
+type NodesDataSource struct {
+	client *client.Client
+}
+
+// NodesDataSourceModel describes the nodes data model.
+type NodesDataSourceModel struct {
+	ProjectID    types.String          `tfsdk:"project_id"`
+	Tags         types.Map             `tfsdk:"tags"`
+	Status       types.String          `tfsdk:"status"`
+	DatacenterID types.String          `tfsdk:"datacenter_id"`
+	FlavourID    types.String          `tfsdk:"flavour_id"`
+	FQDNRegex    types.String          `tfsdk:"fqdn_regex"`
+	Nodes        []NodeDataSourceModel `tfsdk:"nodes"`
+	Id           types.String          `tfsdk:"id"`
+}
+
+func (d *NodesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nodes"
+}
+
+func (d *NodesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The nodes datasource lists the nodes of a project, including nodes that are not managed by this terraform configuration. " +
+			"All filters are optional and combined, only nodes matching every set filter are returned.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project ID to list the nodes of",
+				Required:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Only return nodes having all of these tags with exactly these values",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return nodes with this status (e.g. `NODE_STATUS_RUNNING` or `running`)",
+				Optional:            true,
+			},
+			"datacenter_id": schema.StringAttribute{
+				MarkdownDescription: "Only return nodes located in this datacenter",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"flavour_id": schema.StringAttribute{
+				MarkdownDescription: "Only return nodes of this flavour",
+				Optional:            true,
+				Validators: []validator.String{
+					gpcloudvalidator.UUIDStringValidator{},
+				},
+			},
+			"fqdn_regex": schema.StringAttribute{
+				MarkdownDescription: "Only return nodes whose FQDN matches this regular expression (RE2 syntax)",
+				Optional:            true,
+			},
+			"nodes": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching nodes, ordered by FQDN",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"project_id": schema.StringAttribute{
+							MarkdownDescription: "Project ID the node belongs to",
+							Computed:            true,
+						},
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Node ID",
+							Computed:            true,
+						},
+						"fqdn": schema.StringAttribute{
+							MarkdownDescription: "Fully Qualified Domain Name of the node",
+							Computed:            true,
+						},
+						"ip": schema.StringAttribute{
+							MarkdownDescription: "IP Address of the node",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Node Status",
+							Computed:            true,
+						},
+						"tags": schema.MapAttribute{
+							MarkdownDescription: "Node Tags",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"flavour_id": schema.StringAttribute{
+							MarkdownDescription: "Flavour ID of the node",
+							Computed:            true,
+						},
+						"datacenter_id": schema.StringAttribute{
+							MarkdownDescription: "Datacenter ID the node is located in",
+							Computed:            true,
+						},
+						"image_id": schema.StringAttribute{
+							MarkdownDescription: "Image ID the node is installed with",
+							Computed:            true,
+						},
+						"billing_period": schema.StringAttribute{
+							MarkdownDescription: "Billing period of the node",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Project ID, set for compatibility reasons",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NodesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NodesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data NodesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.FQDNRegex.IsNull() && !data.FQDNRegex.IsUnknown() {
+		if _, err := regexp.Compile(data.FQDNRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("fqdn_regex"), "Invalid Regular Expression", err.Error())
+		}
+	}
+	if !data.Status.IsNull() && !data.Status.IsUnknown() && getNodeStatus(data.Status.ValueString()) == cloudv1.NodeStatus_NODE_STATUS_UNSPECIFIED {
+		resp.Diagnostics.AddAttributeError(path.Root("status"), "Invalid Node Status",
+			fmt.Sprintf("Unknown node status %s.", data.Status.ValueString()))
+	}
+}
+
+func (d *NodesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var fqdnRegex *regexp.Regexp
+	if !data.FQDNRegex.IsNull() {
+		var err error
+		fqdnRegex, err = regexp.Compile(data.FQDNRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("fqdn_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+	tags := map[string]string{}
+	for key, value := range data.Tags.Elements() {
+		if stringValue, ok := value.(types.String); ok {
+			tags[key] = stringValue.ValueString()
+		}
+	}
+
+	nodeList, err := d.client.CloudClient().ListNodes(context.Background(), &cloudv1.ListNodesRequest{
+		ProjectId: data.ProjectID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list nodes, got error: %s", err))
+		return
+	}
+
+	data.Nodes = []NodeDataSourceModel{}
+	for _, node := range nodeList.Nodes {
+		if !data.Status.IsNull() && node.Status != getNodeStatus(data.Status.ValueString()) {
+			continue
+		}
+		if !data.DatacenterID.IsNull() && node.Datacenter.Id != data.DatacenterID.ValueString() {
+			continue
+		}
+		if !data.FlavourID.IsNull() && node.Flavour.Id != data.FlavourID.ValueString() {
+			continue
+		}
+		if fqdnRegex != nil && !fqdnRegex.MatchString(node.Fqdn) {
+			continue
+		}
+		if !hasTags(node, tags) {
+			continue
+		}
+
+		nodeData := NodeDataSourceModel{
+			ProjectID: data.ProjectID,
+		}
+		nodeData.write(node)
+		data.Nodes = append(data.Nodes, nodeData)
+	}
+	sort.Slice(data.Nodes, func(i, j int) bool {
+		return data.Nodes[i].FQDN.ValueString() < data.Nodes[j].FQDN.ValueString()
+	})
+	data.Id = data.ProjectID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// getNodeStatus returns the node status for its full name or the name without prefix (e.g. running).
+func getNodeStatus(value string) cloudv1.NodeStatus {
+	name := strings.ToUpper(value)
+	if !strings.HasPrefix(name, "NODE_STATUS_") {
+		name = "NODE_STATUS_" + name
+	}
+	return cloudv1.NodeStatus(cloudv1.NodeStatus_value[name])
+}
+
+// hasTags checks if the node has all the tags with the same values.
+func hasTags(node *cloudv1.Node, tags map[string]string) bool {
+	for key, value := range tags {
+		if nodeValue, ok := node.Tags[key]; !ok || nodeValue != value {
+			return false
+		}
+	}
+	return true
+}
@@ -229,8 +229,37 @@ func (r *ProjectImage) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 
+// ImportState accepts <project_id>/<id> or <project_id>/name:<name>. Without the project, all accessible projects are searched.
 func (r *ProjectImage) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id := parseImportID(req.ID)
+	if id.Field != "id" && id.Field != "name" {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Unsupported import field %q, expected <project_id>/<id> or <project_id>/name:<name>", id.Field))
+		return
+	}
+
+	projectIDs, err := getImportProjectIDs(r.client, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list projects, got error: %s", err))
+		return
+	}
+
+	for _, projectID := range projectIDs {
+		projectImageResponse, err := r.client.CloudClient().ListProjectImages(context.Background(), &cloudv1.ListProjectImagesRequest{
+			Id: projectID,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list project images, got error: %s", err))
+			return
+		}
+		for _, image := range projectImageResponse.Images {
+			if (id.Field == "id" && image.Id == id.Value) || (id.Field == "name" && image.Name == id.Value) {
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), image.Id)...)
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectID)...)
+				return
+			}
+		}
+	}
+	resp.Diagnostics.AddError("Project Image Not Found", fmt.Sprintf("Unable to find project image with %s %s", id.Field, id.Value))
 }
 
 func (r *ProjectImage) getSourceReader(source string) (io.ReadCloser, error) {
@@ -60,6 +60,7 @@ func (p *GPCloudProvider) Schema(ctx context.Context, req provider.SchemaRequest
 			"client_secret": schema.StringAttribute{
 				MarkdownDescription: "Client Secret",
 				Required:            true,
+				Sensitive:           true,
 			},
 			"username": schema.StringAttribute{
 				MarkdownDescription: "User Email Address",
@@ -68,6 +69,7 @@ func (p *GPCloudProvider) Schema(ctx context.Context, req provider.SchemaRequest
 			"password": schema.StringAttribute{
 				MarkdownDescription: "Password",
 				Optional:            true,
+				Sensitive:           true,
 			},
 			"realm": schema.StringAttribute{
 				MarkdownDescription: "Keycloak Realm",
@@ -86,6 +88,39 @@ func (p *GPCloudProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	// Example client configuration for data sources and resources
+	client, _ := NewClient(data)
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+func (p *GPCloudProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewProject,
+		NewSSHKey,
+		NewNode,
+		NewNodeAction,
+		NewNodeRescue,
+		NewNodeGroup,
+		NewProjectImage,
+		NewBillingProfile,
+	}
+}
+
+func (p *GPCloudProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		NewFlavour,
+		NewImage,
+		NewDataCenter,
+		NewProjectDS,
+		NewNodeDS,
+		NewNodesDS,
+		NewInventory,
+	}
+}
+
+// NewClient creates the GPCloud API client for the given provider configuration.
+func NewClient(data GPCloudProviderModel) (*client2.Client, error) {
 	grpcOpts := []interface{}{}
 	if !data.Endpoint.IsNull() {
 		grpcOpts = append(grpcOpts, client2.EndpointOverrideOption(data.Endpoint.ValueString()))
@@ -112,29 +147,7 @@ func (p *GPCloudProvider) Configure(ctx context.Context, req provider.ConfigureR
 		})
 	}
 
-	// Example client configuration for data sources and resources
-	client, _ := client2.NewClient(grpcOpts...)
-	resp.DataSourceData = client
-	resp.ResourceData = client
-}
-
-func (p *GPCloudProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
-		NewProject,
-		NewSSHKey,
-		NewNode,
-		NewProjectImage,
-		NewBillingProfile,
-	}
-}
-
-func (p *GPCloudProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{
-		NewFlavour,
-		NewImage,
-		NewDataCenter,
-		NewProjectDS,
-	}
+	return client2.NewClient(grpcOpts...)
 }
 
 func New(version string) func() provider.Provider {
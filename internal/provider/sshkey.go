@@ -3,16 +3,20 @@ package provider
 import (
 	cloudv1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/api/cloud/v1"
 	typev1 "buf.build/gen/go/gportal/gportal-cloud/protocolbuffers/go/gpcloud/type/v1"
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/G-PORTAL/gpcloud-go/pkg/gpcloud/client"
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/gpcloudvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"strings"
@@ -21,6 +25,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SSHKey{}
 var _ resource.ResourceWithImportState = &SSHKey{}
+var _ resource.ResourceWithModifyPlan = &SSHKey{}
 
 func NewSSHKey() resource.Resource {
 	return &SSHKey{}
@@ -49,6 +54,9 @@ func (r *SSHKey) Schema(ctx context.Context, req resource.SchemaRequest, resp *r
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "The SSH Key can be referenced in Node deployment to be used for SSH access to the node.\n\n" +
+			"The public key is validated during plan, and its type and fingerprint are computed locally. " +
+			"Changing only whitespace or the comment of the key does not replace it, the change is only stored in the state " +
+			"as GPCloud keeps the key as it got created.\n\n" +
 			"In case the SSH Key already exists remotely, use the `import` command provided by terraform cli to import the resource into the state file.",
 
 		Attributes: map[string]schema.Attribute{
@@ -60,24 +68,25 @@ func (r *SSHKey) Schema(ctx context.Context, req resource.SchemaRequest, resp *r
 				},
 			},
 			"ssh_key_type": schema.StringAttribute{
-				MarkdownDescription: "Type of the SSH Key",
+				MarkdownDescription: "Type of the SSH Key (e.g. `ssh-ed25519`)",
 				Computed:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 			"fingerprint": schema.StringAttribute{
-				MarkdownDescription: "SSHKey Fingerprint",
+				MarkdownDescription: "SSHKey SHA256 Fingerprint, as shown by `ssh-keygen -l`",
 				Computed:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 			"public_key": schema.StringAttribute{
-				MarkdownDescription: "SSH Public Key",
+				MarkdownDescription: "SSH Public Key in authorized_keys format. Changing the key itself replaces the SSH Key",
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						publicKeyRequiresReplace,
+						"Changing the key replaces the SSH Key, whitespace and comment changes are only stored in the state.",
+						"Changing the key replaces the SSH Key, whitespace and comment changes are only stored in the state.",
+					),
+				},
+				Validators: []validator.String{
+					gpcloudvalidator.SSHPublicKeyValidator{},
 				},
 			},
 			"id": schema.StringAttribute{
@@ -123,7 +132,7 @@ func (r *SSHKey) Create(ctx context.Context, req resource.CreateRequest, resp *r
 
 	createRequest := &cloudv1.CreateUserSSHKeyRequest{
 		Name:      data.Name.ValueString(),
-		PublicKey: data.PublicKey.ValueString(),
+		PublicKey: normalizePublicKey(data.PublicKey.ValueString()),
 	}
 
 	createResponse, err := r.client.CloudClient().CreateUserSSHKey(context.Background(), createRequest)
@@ -179,8 +188,47 @@ func (r *SSHKey) Read(ctx context.Context, req resource.ReadRequest, resp *resou
 	}
 }
 
+// ModifyPlan computes the type and fingerprint of the configured public key, so they are known during plan.
+func (r *SSHKey) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan *SSHKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.PublicKey.IsUnknown() {
+		return
+	}
+
+	// Invalid keys are reported by the validator of public_key
+	if key, _, err := gpcloudvalidator.ParseSSHPublicKey(plan.PublicKey.ValueString()); err == nil {
+		plan.Type = types.StringValue(key.Type())
+		plan.Fingerprint = types.StringValue(ssh.FingerprintSHA256(key))
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+}
+
+// Update only stores the public key, as changes other than whitespace and the comment replace the SSH Key.
 func (r *SSHKey) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Client Error", "Unable to update ssh key")
+	var data *SSHKeyModel
+	var state *SSHKeyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = state.Id
+	if data.Type.IsUnknown() {
+		data.Type = state.Type
+	}
+	if data.Fingerprint.IsUnknown() {
+		data.Fingerprint = state.Fingerprint
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *SSHKey) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -207,13 +255,79 @@ func (r *SSHKey) Delete(ctx context.Context, req resource.DeleteRequest, resp *r
 	}
 }
 
+// ImportState accepts the SSH Key ID or name:<name>.
 func (r *SSHKey) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id := parseImportID(req.ID)
+	if id.Field == "id" {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+	if id.Field != "name" {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Unsupported import field %q, expected <id> or name:<name>", id.Field))
+		return
+	}
+
+	sshKeyResponse, err := r.client.CloudClient().ListUserSSHKeys(context.Background(), &cloudv1.ListUserSSHKeysRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list ssh keys, got error: %s", err))
+		return
+	}
+	for _, sshKey := range sshKeyResponse.SshKeys {
+		if sshKey.Name == id.Value {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), sshKey.Id)...)
+			return
+		}
+	}
+	resp.Diagnostics.AddError("SSH Key Not Found", fmt.Sprintf("Unable to find ssh key with name %s", id.Value))
 }
 
 func (sshKeyModel *SSHKeyModel) writeNewKey(sshKey *typev1.SSHKey) {
 	sshKeyModel.Id = types.StringValue(sshKey.Id)
 	sshKeyModel.Name = types.StringValue(sshKey.Name)
+	// Imported keys only get their public key from the API
+	if sshKeyModel.PublicKey.IsNull() || sshKeyModel.PublicKey.IsUnknown() {
+		sshKeyModel.PublicKey = types.StringValue(sshKey.PublicKey)
+	}
+	// Type and fingerprint are computed locally in the format used during plan, the API reports them in its own format
+	for _, publicKey := range []string{sshKeyModel.PublicKey.ValueString(), sshKey.PublicKey} {
+		if key, _, err := gpcloudvalidator.ParseSSHPublicKey(publicKey); err == nil {
+			sshKeyModel.Type = types.StringValue(key.Type())
+			sshKeyModel.Fingerprint = types.StringValue(ssh.FingerprintSHA256(key))
+			return
+		}
+	}
+	// Keys that can not be used anymore (e.g. DSA keys) keep the values reported by the API
 	sshKeyModel.Type = types.StringValue(sshKey.Type.String())
-	sshKeyModel.Fingerprint = types.StringValue(*sshKey.Fingerprint)
+	sshKeyModel.Fingerprint = types.StringNull()
+	if sshKey.Fingerprint != nil {
+		sshKeyModel.Fingerprint = types.StringValue(*sshKey.Fingerprint)
+	}
+}
+
+// publicKeyRequiresReplace forces a replacement only if the key itself changed, not its whitespace or comment.
+func publicKeyRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	planKey, _, err := gpcloudvalidator.ParseSSHPublicKey(req.PlanValue.ValueString())
+	if err != nil {
+		resp.RequiresReplace = true
+		return
+	}
+	stateKey, _, err := gpcloudvalidator.ParseSSHPublicKey(req.StateValue.ValueString())
+	if err != nil {
+		resp.RequiresReplace = true
+		return
+	}
+	resp.RequiresReplace = !bytes.Equal(planKey.Marshal(), stateKey.Marshal())
+}
+
+// normalizePublicKey returns the key as "<type> <base64 key> [comment]" with single spaces and without surrounding whitespace.
+func normalizePublicKey(publicKey string) string {
+	key, comment, err := gpcloudvalidator.ParseSSHPublicKey(publicKey)
+	if err != nil {
+		return strings.TrimSpace(publicKey)
+	}
+	normalized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	if comment = strings.Join(strings.Fields(comment), " "); comment != "" {
+		normalized += " " + comment
+	}
+	return normalized
 }
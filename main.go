@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
+	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/discovery"
 	"github.com/G-PORTAL/terraform-provider-gpcloud/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
@@ -31,6 +33,15 @@ var (
 func main() {
 	var debug bool
 
+	// The discover subcommand generates import blocks and configuration for existing resources,
+	// e.g.: terraform-provider-gpcloud discover -project <project_id> > imported.tf
+	if len(os.Args) > 1 && os.Args[1] == "discover" {
+		if err := discovery.Run(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 